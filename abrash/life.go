@@ -206,14 +206,26 @@ func (m *model) Ingest(f *rle.RLEField) {
 	m.calculateAllNeighbors()
 }
 
+// ToggleCell toggles the state of the cell at the given position, keeping its neighbors' counts up to date.
 func (m *model) ToggleCell(x, y int) {
 	if m.field[y][x].state() {
-		m.field[y][x] = m.field[y][x].vivify()
+		m.makeDead(x, y)
 	} else {
-		m.field[y][x] = m.field[y][x].kill()
+		m.makeAlive(x, y)
 	}
 }
 
+// Export returns the current state of the field.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	for y, row := range m.field {
+		for x, col := range row {
+			f.Field[y][x] = col.state()
+		}
+	}
+	return f
+}
+
 // View builds the entire screen's worth of cells to be printed by returning a • for a living cell or a space for a dead cell.
 func (m *model) String() string {
 	var frame string
@@ -1,9 +1,9 @@
 package abrashchangelist
 
 import (
-	"math"
 	"math/rand"
 
+	"github.com/makyo/gogol/base"
 	"github.com/makyo/gogol/rle"
 )
 
@@ -50,99 +50,22 @@ type model struct {
 	changes []int
 }
 
-// wrapPos wraps a cell position that would otherwise be outside of a rectangular grid.
-func (m *model) wrapPos(pos int) int {
-	return int(math.Abs(float64(pos))) % len(m.field)
-}
-
-// addToNeighbors to all neighboring cells.
+// addToNeighbors adds to all neighboring cells.
 func (m *model) addToNeighbors(pos int) {
 	m.changes = append(m.changes, pos)
-
-	// West
-	newPos := m.wrapPos(pos - 1)
-	m.field[newPos] += 0x1
-	m.changes = append(m.changes, newPos)
-
-	// Northwest
-	newPos = m.wrapPos(pos - m.width - 1)
-	m.field[newPos] += 0x1
-	m.changes = append(m.changes, newPos)
-
-	// North
-	newPos = m.wrapPos(pos - m.width)
-	m.field[newPos] += 0x1
-	m.changes = append(m.changes, newPos)
-
-	// Northeast
-	newPos = m.wrapPos(pos - m.width + 1)
-	m.field[newPos] += 0x1
-	m.changes = append(m.changes, newPos)
-
-	// East
-	newPos = m.wrapPos(pos + 1)
-	m.field[newPos] += 0x1
-	m.changes = append(m.changes, newPos)
-
-	// Southeast
-	newPos = m.wrapPos(pos + m.width + 1)
-	m.field[newPos] += 0x1
-	m.changes = append(m.changes, newPos)
-
-	// South
-	newPos = m.wrapPos(pos + m.width)
-	m.field[newPos] += 0x1
-	m.changes = append(m.changes, newPos)
-
-	// Southwest
-	newPos = m.wrapPos(pos + m.width - 1)
-	m.field[newPos] += 0x1
-	m.changes = append(m.changes, newPos)
+	for _, newPos := range base.Neighbors(pos%m.width, pos/m.width, m.width, m.height) {
+		m.field[newPos] += 0x1
+		m.changes = append(m.changes, newPos)
+	}
 }
 
 // subtractFromNeighbors subtracts from all neighboring cells.
 func (m *model) subtractFromNeighbors(pos int) {
 	m.changes = append(m.changes, pos)
-
-	// West
-	newPos := m.wrapPos(pos - 1)
-	m.field[newPos] -= 0x1
-	m.changes = append(m.changes, newPos)
-
-	// Northwest
-	newPos = m.wrapPos(pos - m.width - 1)
-	m.field[newPos] -= 0x1
-	m.changes = append(m.changes, newPos)
-
-	// North
-	newPos = m.wrapPos(pos - m.width)
-	m.field[newPos] -= 0x1
-	m.changes = append(m.changes, newPos)
-
-	// Northeast
-	newPos = m.wrapPos(pos - m.width + 1)
-	m.field[newPos] -= 0x1
-	m.changes = append(m.changes, newPos)
-
-	// East
-	newPos = m.wrapPos(pos + 1)
-	m.field[newPos] -= 0x1
-	m.changes = append(m.changes, newPos)
-
-	// Southeast
-	newPos = m.wrapPos(pos + m.width + 1)
-	m.field[newPos] -= 0x1
-	m.changes = append(m.changes, newPos)
-
-	// South
-	newPos = m.wrapPos(pos + m.width)
-	m.field[newPos] -= 0x1
-	m.changes = append(m.changes, newPos)
-
-	// Southwest
-	newPos = m.wrapPos(pos + m.width - 1)
-	m.field[newPos] -= 0x1
-	m.changes = append(m.changes, newPos)
+	for _, newPos := range base.Neighbors(pos%m.width, pos/m.width, m.width, m.height) {
+		m.field[newPos] -= 0x1
+		m.changes = append(m.changes, newPos)
+	}
 }
 
 // calculateNeighbors calculates alive neighbors for every cell in the model. It marks all added cells as changed, whether or not they will have, which makes the first generation a little expensive, but that's okay.
@@ -223,13 +146,23 @@ func (m *model) Ingest(f *rle.RLEField) {
 	m.calculateAllNeighbors()
 }
 
+// ToggleCell toggles the state of the cell at the given position, keeping its neighbors' counts up to date.
 func (m *model) ToggleCell(x, y int) {
 	pos := y*m.width + x
 	if m.field[pos].state() {
-		m.field[pos] = m.field[pos].vivify()
+		m.makeDead(pos)
 	} else {
-		m.field[pos] = m.field[pos].kill()
+		m.makeAlive(pos)
+	}
+}
+
+// Export returns the current state of the field.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	for i, c := range m.field {
+		f.Field[i/m.width][i%m.width] = c.state()
 	}
+	return f
 }
 
 // View builds the entire screen's worth of cells to be printed by returning a • for a living cell or a space for a dead cell.
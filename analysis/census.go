@@ -0,0 +1,248 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/rle"
+)
+
+// Once a pattern has settled down, what's left can be counted object by object. Cells are first grouped with anything
+// within two cells of them, which keeps objects such as the beacon, whose halves don't touch in one phase, together.
+// That also lumps together objects which happen to sit close to one another without affecting each other, such as
+// the two blocks of a bi-block, so groups which aren't known objects are split into their touching parts, and those
+// parts put back together into known objects wherever they can be. A split only counts if each of the objects goes on
+// exactly as it would on its own, so that a real object which isn't in the catalogue isn't mistaken for a pile of
+// smaller ones.
+
+// maxParts is the most touching parts a group may be split into before it's given up on as unidentified, as every way
+// of putting them back together is tried.
+const maxParts = 10
+
+// plurals holds the names of objects which don't take an s when there are more than one.
+var plurals = map[string]string{
+	"loaf":  "loaves",
+	"mango": "mangoes",
+}
+
+// Census is a count of the objects in a field.
+type Census struct {
+	// Objects is the number of each known object, by name.
+	Objects map[string]int `json:"objects"`
+
+	// Unidentified is the number of each object which isn't in the catalogue, by its cells in RLE, in whichever
+	// orientation comes first.
+	Unidentified map[string]int `json:"unidentified,omitempty"`
+}
+
+// String lists the objects in the census from most to least common, such as "12 blocks, 5 blinkers, 3 beehives".
+func (c Census) String() string {
+	type entry struct {
+		name  string
+		count int
+	}
+	var entries []entry
+	for name, count := range c.Objects {
+		entries = append(entries, entry{name, count})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].name < entries[j].name
+	})
+
+	var parts []string
+	for _, e := range entries {
+		name := e.name
+		if e.count != 1 {
+			if plural, ok := plurals[name]; ok {
+				name = plural
+			} else {
+				name += "s"
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", e.count, name))
+	}
+	unidentified := 0
+	for _, count := range c.Unidentified {
+		unidentified += count
+	}
+	if unidentified == 1 {
+		parts = append(parts, "1 unidentified object")
+	} else if unidentified > 1 {
+		parts = append(parts, fmt.Sprintf("%d unidentified objects", unidentified))
+	}
+	if len(parts) == 0 {
+		return "nothing"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// TakeCensus counts the objects in the model's field as it is now. Objects are identified by their shapes under
+// Conway's Game of Life, so this suits two-state engines following Life or similar rules.
+func TakeCensus(m base.Model) Census {
+	c := Census{Objects: map[string]int{}, Unidentified: map[string]int{}}
+	for _, group := range components(m.Export(), 2) {
+		for _, object := range identify(group) {
+			if s, ok := shapes[objectKey(object)]; ok {
+				c.Objects[s.name]++
+			} else {
+				c.Unidentified[canonicalRLE(object)]++
+			}
+		}
+	}
+	return c
+}
+
+// objectKey returns the key of a set of points, which isn't affected by where they are.
+func objectKey(points []point) string {
+	key, _, _ := shapeKey(points)
+	return key
+}
+
+// identify splits a group of cells into the objects it is made of. A known object is returned as it is, and so is a
+// group which can't be split into known objects which leave each other be.
+func identify(group []point) [][]point {
+	if _, ok := shapes[objectKey(group)]; ok {
+		return [][]point{group}
+	}
+	parts := touching(group)
+	if len(parts) < 2 || len(parts) > maxParts {
+		return [][]point{group}
+	}
+	if objects := assemble(parts); objects != nil && independent(group, objects) {
+		return objects
+	}
+	return [][]point{group}
+}
+
+// touching splits a set of points into groups of cells which touch, even at a corner.
+func touching(points []point) [][]point {
+	alive := map[point]bool{}
+	for _, p := range points {
+		alive[p] = true
+	}
+	seen := map[point]bool{}
+	var groups [][]point
+	for _, p := range points {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		group := []point{p}
+		for i := 0; i < len(group); i++ {
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					q := point{group[i].x + dx, group[i].y + dy}
+					if alive[q] && !seen[q] {
+						seen[q] = true
+						group = append(group, q)
+					}
+				}
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// assemble puts parts back together into known objects, returning nil if there's no way to do so which uses every
+// part. The first part is joined by as few others as possible, then the same is done with whatever is left.
+func assemble(parts [][]point) [][]point {
+	if len(parts) == 0 {
+		return [][]point{}
+	}
+	rest := parts[1:]
+	for size := 0; size <= len(rest); size++ {
+		for mask := 0; mask < 1<<len(rest); mask++ {
+			if bitCount(mask) != size {
+				continue
+			}
+			object := append([]point{}, parts[0]...)
+			var remaining [][]point
+			for i, part := range rest {
+				if mask&(1<<i) != 0 {
+					object = append(object, part...)
+				} else {
+					remaining = append(remaining, part)
+				}
+			}
+			if _, ok := shapes[objectKey(object)]; !ok {
+				continue
+			}
+			if others := assemble(remaining); others != nil {
+				return append([][]point{object}, others...)
+			}
+		}
+	}
+	return nil
+}
+
+// bitCount returns the number of bits set in n.
+func bitCount(n int) int {
+	count := 0
+	for ; n > 0; n &= n - 1 {
+		count++
+	}
+	return count
+}
+
+// independent returns whether a group of cells evolves exactly as the objects it has been split into would each on
+// their own, over the time it takes all of them to come back around.
+func independent(group []point, objects [][]point) bool {
+	period := 1
+	for _, object := range objects {
+		p := shapes[objectKey(object)].period
+		period = period / gcd(period, p) * p
+	}
+	phases := append([][]point{}, objects...)
+	for gen := 0; gen < period; gen++ {
+		group = lifeStep(group)
+		var together []point
+		for i := range phases {
+			phases[i] = lifeStep(phases[i])
+			together = append(together, phases[i]...)
+		}
+		if len(together) != len(group) {
+			return false
+		}
+		groupKey, groupLeft, groupTop := shapeKey(group)
+		key, left, top := shapeKey(together)
+		if key != groupKey || left != groupLeft || top != groupTop {
+			return false
+		}
+	}
+	return true
+}
+
+// canonicalRLE returns the cells of an object in RLE, without a header, in whichever of its orientations has the
+// smallest key, so that the same object gives the same result however it's turned.
+func canonicalRLE(points []point) string {
+	best := ""
+	var bestPoints []point
+	for _, orient := range orientations {
+		oriented := make([]point, len(points))
+		for i, p := range points {
+			oriented[i] = orient(p)
+		}
+		if key := objectKey(oriented); best == "" || key < best {
+			best, bestPoints = key, oriented
+		}
+	}
+
+	left, top, width, height := bounds(bestPoints)
+	f := rle.New(width, height)
+	for _, p := range bestPoints {
+		f.Field[p.y-top][p.x-left] = true
+	}
+	var body strings.Builder
+	for _, line := range strings.Split(f.Marshal(), "\n") {
+		if line != "" && line[0] != '#' && line[0] != 'x' {
+			body.WriteString(line)
+		}
+	}
+	return strings.TrimSuffix(body.String(), "!")
+}
@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/isotropic"
+	"github.com/makyo/gogol/rle"
+)
+
+// census counts the objects in a pattern on a field of the given size.
+func census(pattern string, size int) Census {
+	f, err := rle.Unmarshal(pattern)
+	So(err, ShouldBeNil)
+	m := isotropic.New(size, size)
+	m.Ingest(f)
+	return TakeCensus(m)
+}
+
+func TestCensus(t *testing.T) {
+	Convey("What's left of the R-pentomino should be counted object by object", t, func() {
+		f, err := rle.Unmarshal("x = 3, y = 3\nb2o$2o$bo!")
+		So(err, ShouldBeNil)
+		m := isotropic.New(200, 200)
+		m.Ingest(f)
+		Stabilize(m, 2000)
+		c := TakeCensus(m)
+		So(c.Objects, ShouldResemble, map[string]int{"block": 8, "beehive": 4, "blinker": 4, "boat": 1, "loaf": 1, "ship": 1})
+		So(c.Unidentified, ShouldBeEmpty)
+		So(c.String(), ShouldEqual, "8 blocks, 4 beehives, 4 blinkers, 1 boat, 1 loaf, 1 ship")
+	})
+
+	Convey("Objects which sit close together without touching each other should be counted separately", t, func() {
+		c := census("x = 2, y = 5\n2o$2o2$2o$2o!", 20)
+		So(c.Objects, ShouldResemble, map[string]int{"block": 2})
+	})
+
+	Convey("Objects whose parts don't touch should be counted as one in every phase", t, func() {
+		f, err := rle.Unmarshal("x = 4, y = 4\n2o$o$3bo$2b2o!")
+		So(err, ShouldBeNil)
+		m := isotropic.New(20, 20)
+		m.Ingest(f)
+		for i := 0; i < 2; i++ {
+			So(TakeCensus(m).Objects, ShouldResemble, map[string]int{"beacon": 1})
+			m.Next()
+		}
+	})
+
+	Convey("Objects which aren't in the catalogue should be given by their cells", t, func() {
+		c := census("x = 5, y = 4\n2bo$bobo$bobo$2ob2o!", 20)
+		So(c.Objects, ShouldBeEmpty)
+		So(c.Unidentified, ShouldHaveLength, 1)
+		So(census("x = 4, y = 5\n2o$bo$o2bo$3o$2bo!", 20).Unidentified, ShouldHaveLength, 1)
+		So(c.String(), ShouldEqual, "1 unidentified object")
+	})
+
+	Convey("An empty field should have nothing in it", t, func() {
+		So(census("x = 1, y = 1\nb!", 20).String(), ShouldEqual, "nothing")
+	})
+}
+
+func TestCatalogue(t *testing.T) {
+	Convey("Still lifes should have period 1 and oscillators their own periods", t, func() {
+		periods := map[string]int{}
+		for _, s := range shapes {
+			periods[s.name] = s.period
+		}
+		So(periods["block"], ShouldEqual, 1)
+		So(periods["eater"], ShouldEqual, 1)
+		So(periods["beacon"], ShouldEqual, 2)
+		So(periods["pulsar"], ShouldEqual, 3)
+		So(periods["pentadecathlon"], ShouldEqual, 15)
+	})
+}
@@ -0,0 +1,207 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/makyo/gogol/rle"
+)
+
+// Objects are groups of living cells close enough to one another to be counted as one thing, such as a block or a
+// glider. Since the field wraps around, the cells of an object are given coordinates which don't, starting from
+// wherever the first cell found happens to be, so that an object straddling an edge stays in one piece.
+
+// A point is the position of a living cell.
+type point struct {
+	x, y int
+}
+
+// components splits the living cells in a field into groups, where two cells are in the same group if they are within
+// the given distance of one another in both directions. A distance of 1 groups cells which touch, even at a corner,
+// and a distance of 2 also groups cells with a single dead cell between them.
+func components(f *rle.RLEField, distance int) [][]point {
+	seen := make([]bool, f.Width*f.Height)
+
+	var groups [][]point
+	for y, row := range f.Field {
+		for x, col := range row {
+			if !col || seen[y*f.Width+x] {
+				continue
+			}
+			seen[y*f.Width+x] = true
+			group := []point{{x, y}}
+			for i := 0; i < len(group); i++ {
+				p := group[i]
+				for dy := -distance; dy <= distance; dy++ {
+					wy := ((p.y+dy)%f.Height + f.Height) % f.Height
+					for dx := -distance; dx <= distance; dx++ {
+						wx := ((p.x+dx)%f.Width + f.Width) % f.Width
+						if f.Field[wy][wx] && !seen[wy*f.Width+wx] {
+							seen[wy*f.Width+wx] = true
+							group = append(group, point{p.x + dx, p.y + dy})
+						}
+					}
+				}
+			}
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// bounds returns the smallest box holding every point.
+func bounds(points []point) (left, top, width, height int) {
+	minX, minY, maxX, maxY := points[0].x, points[0].y, points[0].x, points[0].y
+	for _, p := range points[1:] {
+		if p.x < minX {
+			minX = p.x
+		}
+		if p.x > maxX {
+			maxX = p.x
+		}
+		if p.y < minY {
+			minY = p.y
+		}
+		if p.y > maxY {
+			maxY = p.y
+		}
+	}
+	return minX, minY, maxX - minX + 1, maxY - minY + 1
+}
+
+// shapeKey returns a key which is the same for any two sets of points in the same arrangement, wherever they are, in
+// the same form as Normalize. It also returns the top left corner of the points' box.
+func shapeKey(points []point) (key string, left, top int) {
+	left, top, width, height := bounds(points)
+	cells := make([]byte, width*height)
+	for _, p := range points {
+		cells[(p.y-top)*width+p.x-left] = 1
+	}
+	return fmt.Sprintf("%dx%d:", width, height) + string(cells), left, top
+}
+
+// parsePoints returns the living cells of a two-state pattern given in RLE.
+func parsePoints(pattern string) []point {
+	f, err := rle.Unmarshal(pattern)
+	if err != nil {
+		panic(err)
+	}
+	var points []point
+	for y, row := range f.Field {
+		for x, col := range row {
+			if col {
+				points = append(points, point{x, y})
+			}
+		}
+	}
+	return points
+}
+
+// lifeStep returns the next generation of a set of points under Conway's Game of Life, on a plane with no edges.
+func lifeStep(points []point) []point {
+	alive := map[point]bool{}
+	counts := map[point]int{}
+	for _, p := range points {
+		alive[p] = true
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if dx != 0 || dy != 0 {
+					counts[point{p.x + dx, p.y + dy}]++
+				}
+			}
+		}
+	}
+	var next []point
+	for p, count := range counts {
+		if count == 3 || count == 2 && alive[p] {
+			next = append(next, p)
+		}
+	}
+	return next
+}
+
+// orientations holds the eight rotations and reflections of the plane.
+var orientations = []func(p point) point{
+	func(p point) point { return p },
+	func(p point) point { return point{-p.y, p.x} },
+	func(p point) point { return point{-p.x, -p.y} },
+	func(p point) point { return point{p.y, -p.x} },
+	func(p point) point { return point{-p.x, p.y} },
+	func(p point) point { return point{p.x, -p.y} },
+	func(p point) point { return point{p.y, p.x} },
+	func(p point) point { return point{-p.y, -p.x} },
+}
+
+// A shape is a known object in one of its phases and orientations.
+type shape struct {
+	name string
+
+	// period is the period of the object, and dx and dy how far this phase of it moves in that time.
+	period int
+	dx, dy int
+}
+
+// shapes holds every known object in every phase and orientation, by key.
+var shapes = map[string]shape{}
+
+// known adds an object to the shapes, given its name and a phase of it in RLE, by running it under Conway's Game of
+// Life until it repeats.
+func known(name, pattern string) {
+	points := parsePoints(pattern)
+	key, left, top := shapeKey(points)
+	phases := [][]point{points}
+	for period := 1; ; period++ {
+		next := lifeStep(phases[len(phases)-1])
+		nextKey, nextLeft, nextTop := shapeKey(next)
+		if nextKey != key {
+			phases = append(phases, next)
+			continue
+		}
+		for _, phase := range phases {
+			for _, orient := range orientations {
+				oriented := make([]point, len(phase))
+				for i, p := range phase {
+					oriented[i] = orient(p)
+				}
+				d := orient(point{nextLeft - left, nextTop - top})
+				k, _, _ := shapeKey(oriented)
+				shapes[k] = shape{name: name, period: period, dx: d.x, dy: d.y}
+			}
+		}
+		return
+	}
+}
+
+func init() {
+	// Still lifes.
+	known("block", "x = 2, y = 2\n2o$2o!")
+	known("beehive", "x = 4, y = 3\nb2o$o2bo$b2o!")
+	known("loaf", "x = 4, y = 4\nb2o$o2bo$bobo$2bo!")
+	known("boat", "x = 3, y = 3\n2o$obo$bo!")
+	known("ship", "x = 3, y = 3\n2o$obo$b2o!")
+	known("tub", "x = 3, y = 3\nbo$obo$bo!")
+	known("pond", "x = 4, y = 4\nb2o$o2bo$o2bo$b2o!")
+	known("long boat", "x = 4, y = 4\n2o$obo$bobo$2bo!")
+	known("long ship", "x = 4, y = 4\n2o$obo$bobo$2b2o!")
+	known("barge", "x = 4, y = 4\nbo$obo$bobo$2bo!")
+	known("long barge", "x = 5, y = 5\nbo$obo$bobo$2bobo$3bo!")
+	known("mango", "x = 5, y = 4\nb2o$o2bo$bo2bo$2b2o!")
+	known("aircraft carrier", "x = 4, y = 3\n2o$o2bo$2b2o!")
+	known("snake", "x = 4, y = 2\n2obo$ob2o!")
+	known("eater", "x = 4, y = 4\n2o$obo$2bo$2b2o!")
+	known("ship-tie", "x = 6, y = 6\n2o$obo$b2o$3b2o$3bobo$4b2o!")
+
+	// Oscillators.
+	known("blinker", "x = 3, y = 1\n3o!")
+	known("toad", "x = 4, y = 2\nb3o$3o!")
+	known("beacon", "x = 4, y = 4\n2o$o$3bo$2b2o!")
+	known("clock", "x = 4, y = 4\n2bo$obo$bobo$bo!")
+	known("pulsar", "x = 13, y = 13\n"+
+		"2b3o3b3o2$o4bobo4bo$o4bobo4bo$o4bobo4bo$2b3o3b3o2$2b3o3b3o$o4bobo4bo$o4bobo4bo$o4bobo4bo2$2b3o3b3o!")
+	known("pentadecathlon", "x = 10, y = 3\n2bo4bo$2ob4ob2o$2bo4bo!")
+
+	// Spaceships.
+	known("glider", "x = 3, y = 3\nbo$2bo$3o!")
+	known("lightweight spaceship", "x = 5, y = 4\nbo2bo$o4b$o3bo$4o!")
+	known("middleweight spaceship", "x = 6, y = 5\n2bo3b$o3bob$5bo$o4bo$b5o!")
+	known("heavyweight spaceship", "x = 7, y = 5\n2b2o3b$o4bob$6bo$o5bo$b6o!")
+}
@@ -0,0 +1,283 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/rle"
+)
+
+// A pattern is periodic once some generation looks exactly like an earlier one, give or take where it is on the
+// field. To find that, each generation is cropped to the smallest box holding every living cell and the result is
+// hashed; the first time a hash comes up again, the difference in generations is the period, and the difference in
+// where the boxes were is the displacement.
+//
+// The fields are tori, so a box may wrap around an edge. The box along each axis starts just after the largest gap
+// with no living cells, which keeps a spaceship in one piece as it crosses an edge.
+
+// Kinds of pattern.
+const (
+	// Dead patterns have no living cells left.
+	Dead = "dead"
+
+	// Still lifes don't change from one generation to the next.
+	StillLife = "still life"
+
+	// Oscillators return to where they started after more than one generation.
+	Oscillator = "oscillator"
+
+	// Spaceships return to their shape after some number of generations, but somewhere else.
+	Spaceship = "spaceship"
+
+	// Growing patterns don't repeat, but keep gaining cells and spreading out as they go, as puffers and guns do.
+	Growing = "growing"
+
+	// Unknown patterns haven't repeated or grown steadily within the generations they were run for.
+	Unknown = "unknown"
+)
+
+// Result describes the periodic behavior of a pattern.
+type Result struct {
+	Kind string `json:"kind"`
+
+	// Period is the number of generations after which the pattern repeats, and DX and DY how far it has moved when it
+	// does, with positive values to the right and down.
+	Period int `json:"period,omitempty"`
+	DX     int `json:"dx"`
+	DY     int `json:"dy"`
+
+	// Start is the first generation of the repeating cycle, and Population the number of living cells then, or at the
+	// last generation run if the pattern never repeated.
+	Start      int `json:"start"`
+	Population int `json:"population"`
+
+	// Generations is the number of generations the pattern was run for.
+	Generations int `json:"generations"`
+
+	// Cramped is set if the run was cut short because the pattern spread too far across the field to be told apart
+	// from itself wrapping around the edges, in which case it may need a larger field.
+	Cramped bool `json:"cramped,omitempty"`
+}
+
+// gcd returns the greatest common divisor of two non-negative numbers.
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// abs returns the absolute value of a number.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Direction returns which way a spaceship travels: orthogonally, diagonally, or obliquely. It is empty for patterns
+// which don't move.
+func (r Result) Direction() string {
+	dx, dy := abs(r.DX), abs(r.DY)
+	switch {
+	case dx == 0 && dy == 0:
+		return ""
+	case dx == 0 || dy == 0:
+		return "orthogonal"
+	case dx == dy:
+		return "diagonal"
+	default:
+		return "oblique"
+	}
+}
+
+// Speed returns the speed of a spaceship in the usual notation, where c is one cell per generation: c/4 for a glider,
+// 2c/5 for a spaceship moving two cells every five generations, and (2,1)c/6 for one moving two cells one way and one
+// the other every six. It is empty for patterns which don't move.
+func (r Result) Speed() string {
+	dx, dy := abs(r.DX), abs(r.DY)
+	if dx < dy {
+		dx, dy = dy, dx
+	}
+	if dx == 0 || r.Period == 0 {
+		return ""
+	}
+	if r.Direction() == "oblique" {
+		d := gcd(gcd(dx, dy), r.Period)
+		return fmt.Sprintf("(%d,%d)c/%d", dx/d, dy/d, r.Period/d)
+	}
+	d := gcd(dx, r.Period)
+	if dx == d {
+		return fmt.Sprintf("c/%d", r.Period/d)
+	}
+	return fmt.Sprintf("%dc/%d", dx/d, r.Period/d)
+}
+
+// String describes the result, such as "p3 oscillator" or "c/4 diagonal spaceship".
+func (r Result) String() string {
+	switch r.Kind {
+	case Oscillator:
+		return fmt.Sprintf("p%d oscillator", r.Period)
+	case Spaceship:
+		return fmt.Sprintf("%s %s spaceship", r.Speed(), r.Direction())
+	case Growing:
+		return "growing pattern, such as a puffer or gun"
+	case Unknown:
+		if r.Cramped {
+			return fmt.Sprintf("unknown; reached the edges of the field by generation %d", r.Generations)
+		}
+		return fmt.Sprintf("unknown; no period within %d generations", r.Generations)
+	}
+	return r.Kind
+}
+
+// span returns the start and length of the shortest stretch of a circle of positions which holds every occupied one.
+// If none are occupied, the length is zero.
+func span(occupied []bool) (start, length int) {
+	n := len(occupied)
+	first := -1
+	for i, o := range occupied {
+		if o {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return 0, 0
+	}
+
+	// Go once around the circle from the first occupied position, back to it, finding the largest gap on the way.
+	gapStart, gapLength, run := 0, 0, 0
+	for i := 1; i <= n; i++ {
+		pos := (first + i) % n
+		if !occupied[pos] {
+			run++
+			continue
+		}
+		if run > gapLength {
+			gapStart, gapLength = (pos-run+n)%n, run
+		}
+		run = 0
+	}
+	return (gapStart + gapLength) % n, n - gapLength
+}
+
+// Normalize returns the smallest box holding every living cell in the field, wrapping around the edges if that makes
+// it smaller, along with a key which is the same for any two fields holding the same cells in the same arrangement,
+// wherever they are.
+func Normalize(f *rle.RLEField) (key string, left, top, width, height int) {
+	columns := make([]bool, f.Width)
+	rows := make([]bool, f.Height)
+	for y, row := range f.Field {
+		for x, col := range row {
+			if col {
+				columns[x] = true
+				rows[y] = true
+			}
+		}
+	}
+	left, width = span(columns)
+	top, height = span(rows)
+
+	var k strings.Builder
+	fmt.Fprintf(&k, "%dx%d:", width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			k.WriteByte(byte(f.State((left+x)%f.Width, (top+y)%f.Height)))
+		}
+	}
+	return k.String(), left, top, width, height
+}
+
+// population counts the living cells in a field.
+func population(f *rle.RLEField) int {
+	count := 0
+	for _, row := range f.Field {
+		for _, col := range row {
+			if col {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// wrapped returns the shortest distance along a circle of the given size that is the same as d.
+func wrapped(d, size int) int {
+	d = (d%size + size) % size
+	if d > size/2 {
+		d -= size
+	}
+	return d
+}
+
+// seen is where and when a shape was first seen.
+type seen struct {
+	generation, left, top int
+}
+
+// Period runs the model for up to the given number of generations, stopping as soon as the pattern repeats, and
+// returns how it behaves. It also stops early if the pattern comes to cover more than three quarters of the field
+// across or down, since past that point it may be running into itself around the edges.
+func Period(m base.Model, generations int) Result {
+	history := map[string]seen{}
+	var populations, areas []int
+	f := m.Export()
+	for gen := 0; ; gen++ {
+		key, left, top, width, height := Normalize(f)
+		pop := population(f)
+		if first, ok := history[key]; ok {
+			r := Result{
+				Period:      gen - first.generation,
+				DX:          wrapped(left-first.left, f.Width),
+				DY:          wrapped(top-first.top, f.Height),
+				Start:       first.generation,
+				Population:  pop,
+				Generations: gen,
+			}
+			switch {
+			case pop == 0:
+				r.Kind, r.Period = Dead, 0
+			case r.DX != 0 || r.DY != 0:
+				r.Kind = Spaceship
+			case r.Period == 1:
+				r.Kind = StillLife
+			default:
+				r.Kind = Oscillator
+			}
+			return r
+		}
+		if width > f.Width*3/4 || height > f.Height*3/4 {
+			return Result{Kind: Unknown, Start: gen, Population: pop, Generations: gen, Cramped: true}
+		}
+		history[key] = seen{gen, left, top}
+		populations = append(populations, pop)
+		areas = append(areas, width*height)
+		if gen == generations {
+			break
+		}
+		m.Next()
+		f = m.Export()
+	}
+
+	// Patterns which never repeat are growing if the most cells they have keeps going up from each quarter of the run
+	// to the next, and they end up spread over a larger area than they were halfway through.
+	r := Result{Kind: Unknown, Start: generations, Population: populations[generations], Generations: generations}
+	growing := generations >= 4 && areas[generations] > areas[generations/2]
+	most := -1
+	for quarter := 0; quarter < 4 && growing; quarter++ {
+		peak := 0
+		for _, pop := range populations[quarter*generations/4 : (quarter+1)*generations/4+1] {
+			if pop > peak {
+				peak = pop
+			}
+		}
+		growing = peak > most
+		most = peak
+	}
+	if growing {
+		r.Kind = Growing
+	}
+	return r
+}
@@ -0,0 +1,105 @@
+package analysis
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/isotropic"
+	"github.com/makyo/gogol/rle"
+)
+
+// run analyzes a pattern on a field of the given size.
+func run(pattern string, width, height, generations int) Result {
+	f, err := rle.Unmarshal(pattern)
+	So(err, ShouldBeNil)
+	m := isotropic.New(width, height)
+	if f.Rule != "" {
+		So(m.SetRule(f.Rule), ShouldBeNil)
+	}
+	m.Ingest(f)
+	return Period(m, generations)
+}
+
+func TestPeriod(t *testing.T) {
+	Convey("Patterns should be classified by how they repeat", t, func() {
+		for pattern, expected := range map[string]string{
+			"x = 2, y = 2\n2o$2o!":             "still life",
+			"x = 3, y = 1\n3o!":                "p2 oscillator",
+			"x = 5, y = 4\nbo2bo$o4b$o3bo$4o!": "c/2 orthogonal spaceship",
+			"x = 3, y = 3\nbo$2bo$3o!":         "c/4 diagonal spaceship",
+			"x = 1, y = 1\no!":                 "dead",
+			"x = 4, y = 4\nb2o$o2bo$o2bo$b2o!": "still life",
+			"x = 3, y = 3\nb2o$2o$bo!":         "unknown; no period within 50 generations",
+		} {
+			So(run(pattern, 40, 40, 50).String(), ShouldEqual, expected)
+		}
+	})
+
+	Convey("Patterns which keep spreading should be growing", t, func() {
+		So(run("x = 1, y = 1, rule = B1/S012345678\no!", 200, 200, 50).Kind, ShouldEqual, Growing)
+	})
+
+	Convey("Patterns which spread too far across the field should be cut short", t, func() {
+		r := run("x = 1, y = 1, rule = B1/S012345678\no!", 20, 20, 50)
+		So(r.Kind, ShouldEqual, Unknown)
+		So(r.Cramped, ShouldBeTrue)
+		So(r.Generations, ShouldBeLessThan, 50)
+		So(r.String(), ShouldStartWith, "unknown; reached the edges of the field")
+	})
+
+	Convey("Spaceships should be followed across the edges of the field", t, func() {
+		r := run("x = 3, y = 3\nbo$2bo$3o!", 8, 8, 100)
+		So(r.Kind, ShouldEqual, Spaceship)
+		So([]int{r.Period, r.DX, r.DY}, ShouldResemble, []int{4, 1, 1})
+	})
+
+	Convey("Patterns which take a while to settle should say when they start repeating", t, func() {
+		// A pre-block, which becomes a block one generation later.
+		r := run("x = 2, y = 2\n2o$o!", 10, 10, 20)
+		So(r.Kind, ShouldEqual, StillLife)
+		So(r.Start, ShouldEqual, 1)
+		So(r.Population, ShouldEqual, 4)
+	})
+}
+
+func TestSpeed(t *testing.T) {
+	Convey("Speeds should be written in the usual notation", t, func() {
+		for _, s := range []struct {
+			result           Result
+			speed, direction string
+		}{
+			{Result{Period: 4, DX: 1, DY: 1}, "c/4", "diagonal"},
+			{Result{Period: 4, DX: -2}, "c/2", "orthogonal"},
+			{Result{Period: 5, DY: 2}, "2c/5", "orthogonal"},
+			{Result{Period: 6, DX: 1, DY: -2}, "(2,1)c/6", "oblique"},
+			{Result{Period: 12, DX: 2, DY: 4}, "(2,1)c/6", "oblique"},
+			{Result{Period: 3}, "", ""},
+		} {
+			So(s.result.Speed(), ShouldEqual, s.speed)
+			So(s.result.Direction(), ShouldEqual, s.direction)
+		}
+	})
+}
+
+func TestSpan(t *testing.T) {
+	Convey("Spans should be as short as they can be, wrapping around if need be", t, func() {
+		for _, s := range []struct {
+			occupied      string
+			start, length int
+		}{
+			{"..oo..o...", 2, 5},
+			{"o.......oo", 8, 3},
+			{"..........", 0, 0},
+			{"oooooooooo", 0, 10},
+			{"....o.....", 4, 1},
+		} {
+			occupied := make([]bool, len(s.occupied))
+			for i, c := range s.occupied {
+				occupied[i] = c == 'o'
+			}
+			start, length := span(occupied)
+			So([]int{start, length}, ShouldResemble, []int{s.start, s.length})
+		}
+	})
+}
@@ -0,0 +1,259 @@
+package analysis
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/rle"
+)
+
+// A soup search runs a great many random patterns, called soups, until they settle, and counts what they leave behind,
+// in the manner of apgsearch (see: https://conwaylife.com/wiki/Apgsearch ). Over enough soups, this gives how common
+// each object is, and turns up the rare ones along with soups which make them.
+
+// SoupSize is the width and height of the square of random cells which makes up a soup.
+const SoupSize = 16
+
+// Symmetries holds the orientations a soup is the same under, by name, as indexes into orientations: C1 for no
+// symmetry, C2 and C4 for half and quarter turns, D2 for a mirror image from left to right, D4 for mirror images both
+// ways, and D8 for all of the above.
+var Symmetries = map[string][]int{
+	"C1": {0},
+	"C2": {0, 2},
+	"C4": {0, 1, 2, 3},
+	"D2": {0, 4},
+	"D4": {0, 2, 4, 5},
+	"D8": {0, 1, 2, 3, 4, 5, 6, 7},
+}
+
+// searchEvery is how many generations go by between looking for escaping spaceships and checking whether a soup has
+// settled. Only what a soup leaves behind is counted, not how long it took, so there's no need to look every
+// generation, and as it's a multiple of the most common periods, most soups are caught soon after they settle.
+const searchEvery = 30
+
+// rare is the most times an object may have turned up for it to still be rare enough to keep soups which make it,
+// and maxSamples the most soups kept for any one object.
+const (
+	rare       = 10
+	maxSamples = 3
+)
+
+// Soup returns the soup with the given index for a seed, which is always the same for the same seed, index, and
+// symmetry.
+func Soup(seed int64, index int, symmetry string) (*rle.RLEField, error) {
+	orients, ok := Symmetries[symmetry]
+	if !ok {
+		return nil, fmt.Errorf("Unknown symmetry %q - must be one of C1, C2, C4, D2, D4, or D8", symmetry)
+	}
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%d", seed, index)
+	r := rand.New(rand.NewSource(int64(h.Sum64())))
+	cells := make([]bool, SoupSize*SoupSize)
+	for i := range cells {
+		cells[i] = r.Intn(2) == 0
+	}
+
+	// Each cell takes its state from whichever cell comes first of those it's carried to by the symmetry, turning
+	// about the middle of the soup, which is at the corner of four cells.
+	f := rle.New(SoupSize, SoupSize)
+	f.Comments = []string{fmt.Sprintf("Soup %d of seed %d, symmetry %s", index, seed, symmetry)}
+	for y := 0; y < SoupSize; y++ {
+		for x := 0; x < SoupSize; x++ {
+			first := y*SoupSize + x
+			for _, o := range orients {
+				p := orientations[o](point{2*x - SoupSize + 1, 2*y - SoupSize + 1})
+				if i := (p.y+SoupSize-1)/2*SoupSize + (p.x+SoupSize-1)/2; i < first {
+					first = i
+				}
+			}
+			f.Field[y][x] = cells[first]
+		}
+	}
+	return f, nil
+}
+
+// A Sample is a soup which made something of interest, given by its seed and index along with the soup itself in RLE.
+type Sample struct {
+	Seed    int64  `json:"seed"`
+	Index   int    `json:"index"`
+	Pattern string `json:"pattern"`
+}
+
+// A SearchRun is a single run of a soup search, given by its seed and the number of soups run.
+type SearchRun struct {
+	Seed  int64 `json:"seed"`
+	Soups int   `json:"soups"`
+}
+
+// SearchResults holds the totals of one or more soup searches with the same symmetry.
+type SearchResults struct {
+	Symmetry string      `json:"symmetry"`
+	Soups    int         `json:"soups"`
+	Runs     []SearchRun `json:"runs"`
+
+	// Objects is the number of each object left behind by soups, including escaping spaceships, by name. Objects
+	// which aren't in the catalogue are named "unidentified", followed by their cells in RLE.
+	Objects map[string]int `json:"objects"`
+
+	// Samples holds soups which made each of the rare objects, by name.
+	Samples map[string][]Sample `json:"samples"`
+
+	// Unstable is the number of soups which didn't settle within the generations they were run for, which includes
+	// those which grow forever, and UnstableSamples holds some of them.
+	Unstable        int      `json:"unstable"`
+	UnstableSamples []Sample `json:"unstableSamples,omitempty"`
+}
+
+// NewSearchResults creates empty results for soups with the given symmetry.
+func NewSearchResults(symmetry string) *SearchResults {
+	return &SearchResults{
+		Symmetry: symmetry,
+		Objects:  map[string]int{},
+		Samples:  map[string][]Sample{},
+	}
+}
+
+// String lists the objects found from most to least common, along with how often they turned up.
+func (r *SearchResults) String() string {
+	names := make([]string, 0, len(r.Objects))
+	for name := range r.Objects {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if r.Objects[names[i]] != r.Objects[names[j]] {
+			return r.Objects[names[i]] > r.Objects[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "%d soups with symmetry %s, %d of which didn't settle\n", r.Soups, r.Symmetry, r.Unstable)
+	for _, name := range names {
+		fmt.Fprintf(&out, "%10d  %s\n", r.Objects[name], name)
+	}
+	return out.String()
+}
+
+// add adds what a single soup left behind to the results.
+func (r *SearchResults) add(sample Sample, c *Census) {
+	r.Soups++
+	if c == nil {
+		r.Unstable++
+		if len(r.UnstableSamples) < maxSamples {
+			r.UnstableSamples = append(r.UnstableSamples, sample)
+		}
+		return
+	}
+	counts := map[string]int{}
+	for name, count := range c.Objects {
+		counts[name] += count
+	}
+	for cells, count := range c.Unidentified {
+		counts["unidentified "+cells] += count
+	}
+	for name, count := range counts {
+		r.Objects[name] += count
+		if r.Objects[name] > rare {
+			delete(r.Samples, name)
+		} else if len(r.Samples[name]) < maxSamples {
+			r.Samples[name] = append(r.Samples[name], sample)
+		}
+	}
+}
+
+// SearchOptions says which soups a search runs and how.
+type SearchOptions struct {
+	Seed     int64
+	Soups    int
+	Symmetry string
+
+	// Generations is the most generations a soup is run for before it's counted as unstable, and Size the width and
+	// height of the field it's run on.
+	Generations int
+	Size        int
+
+	// Workers is the number of soups run at once, which defaults to the number of CPUs.
+	Workers int
+}
+
+// soupResult is what a single soup left behind, or nil for the census if it didn't settle.
+type soupResult struct {
+	sample Sample
+	census *Census
+}
+
+// Search runs soups using models made by newModel, which must follow Conway's Game of Life, and adds what they leave
+// behind to the results. Soups are run on all CPUs at once, but are added to the results in order, so the same search
+// always gives the same results.
+func Search(opts SearchOptions, newModel func(width, height int) base.Model, results *SearchResults) error {
+	if results.Symmetry != opts.Symmetry {
+		return fmt.Errorf("Can't add soups with symmetry %s to results for symmetry %s",
+			opts.Symmetry, results.Symmetry)
+	}
+	if _, err := Soup(opts.Seed, 0, opts.Symmetry); err != nil {
+		return err
+	}
+	if opts.Size < SoupSize {
+		return fmt.Errorf("Field must be at least %d cells across to hold a soup", SoupSize)
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+
+	indexes := make(chan int)
+	done := make(chan soupResult)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				f, _ := Soup(opts.Seed, index, opts.Symmetry)
+				m := newModel(opts.Size, opts.Size)
+				m.Ingest(f)
+				result := soupResult{sample: Sample{Seed: opts.Seed, Index: index, Pattern: f.Marshal()}}
+				if s := settle(m, opts.Generations, searchEvery); s.Stable {
+					c := TakeCensus(m)
+					for name, count := range s.Ships {
+						c.Objects[name] += count
+					}
+					result.census = &c
+				}
+				done <- result
+			}
+		}()
+	}
+	go func() {
+		for index := 0; index < opts.Soups; index++ {
+			indexes <- index
+		}
+		close(indexes)
+		wg.Wait()
+		close(done)
+	}()
+
+	// Soups finish out of order, so hold on to each until those before it are in.
+	pending := map[int]soupResult{}
+	next := 0
+	for result := range done {
+		pending[result.sample.Index] = result
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			results.add(r.sample, r.census)
+			delete(pending, next)
+			next++
+		}
+	}
+	results.Runs = append(results.Runs, SearchRun{Seed: opts.Seed, Soups: opts.Soups})
+	return nil
+}
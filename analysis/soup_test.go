@@ -0,0 +1,96 @@
+package analysis
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/abrash"
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/rle"
+)
+
+// turned returns a soup turned or flipped by one of the orientations, about its middle.
+func turned(f *rle.RLEField, o int) *rle.RLEField {
+	t := rle.New(f.Width, f.Height)
+	for y, row := range f.Field {
+		for x, col := range row {
+			p := orientations[o](point{2*x - SoupSize + 1, 2*y - SoupSize + 1})
+			t.Field[(p.y+SoupSize-1)/2][(p.x+SoupSize-1)/2] = col
+		}
+	}
+	return t
+}
+
+func TestSoup(t *testing.T) {
+	Convey("Soups should be the same for the same seed and index", t, func() {
+		a, err := Soup(1, 5, "C1")
+		So(err, ShouldBeNil)
+		b, _ := Soup(1, 5, "C1")
+		c, _ := Soup(1, 6, "C1")
+		d, _ := Soup(2, 5, "C1")
+		So(a.Field, ShouldResemble, b.Field)
+		So(a.Field, ShouldNotResemble, c.Field)
+		So(a.Field, ShouldNotResemble, d.Field)
+		So(a.Width, ShouldEqual, SoupSize)
+		So(a.Height, ShouldEqual, SoupSize)
+	})
+
+	Convey("Soups should be the same under each orientation of their symmetry", t, func() {
+		for name, orients := range Symmetries {
+			f, err := Soup(3, 0, name)
+			So(err, ShouldBeNil)
+			for _, o := range orients {
+				So(turned(f, o).Field, ShouldResemble, f.Field)
+			}
+		}
+		f, _ := Soup(3, 0, "C2")
+		So(turned(f, 1).Field, ShouldNotResemble, f.Field)
+		So(turned(f, 4).Field, ShouldNotResemble, f.Field)
+	})
+
+	Convey("Unknown symmetries should return an error", t, func() {
+		_, err := Soup(1, 0, "C3")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	newModel := func(width, height int) base.Model { return abrash.New(width, height) }
+	opts := SearchOptions{Seed: 4, Soups: 4, Symmetry: "C1", Generations: 3000, Size: 64}
+
+	Convey("A search should count what its soups leave behind", t, func() {
+		r := NewSearchResults("C1")
+		So(Search(opts, newModel, r), ShouldBeNil)
+		So(r.Soups, ShouldEqual, 4)
+		So(r.Runs, ShouldResemble, []SearchRun{{Seed: 4, Soups: 4}})
+		So(r.Objects["block"], ShouldBeGreaterThan, 0)
+		So(r.Samples["block"], ShouldBeEmpty)
+		for name, samples := range r.Samples {
+			So(r.Objects[name], ShouldBeLessThanOrEqualTo, rare)
+			So(len(samples), ShouldBeLessThanOrEqualTo, maxSamples)
+			So(samples[0].Seed, ShouldEqual, 4)
+			So(samples[0].Pattern, ShouldStartWith, "#C Soup")
+		}
+		So(r.String(), ShouldStartWith, "4 soups with symmetry C1")
+
+		Convey("And give the same results however many soups are run at once", func() {
+			one := NewSearchResults("C1")
+			opts.Workers = 1
+			So(Search(opts, newModel, one), ShouldBeNil)
+			So(one, ShouldResemble, r)
+		})
+
+		Convey("And add to the results of earlier searches", func() {
+			opts.Seed = 5
+			So(Search(opts, newModel, r), ShouldBeNil)
+			So(r.Soups, ShouldEqual, 8)
+			So(r.Runs, ShouldHaveLength, 2)
+		})
+	})
+
+	Convey("Searches shouldn't mix symmetries", t, func() {
+		opts.Symmetry = "D2"
+		So(Search(opts, newModel, NewSearchResults("C1")), ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,188 @@
+package analysis
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/rle"
+)
+
+// Methuselahs run for thousands of generations before settling down into still lifes and oscillators, usually
+// throwing off gliders along the way. Those gliders never stop moving, so the pattern as a whole never repeats, and on
+// a torus, they eventually come back around and crash into what was left behind. Instead, spaceships which are on
+// their way out, which nothing else on the field will ever come near, are taken off the field and counted, and the
+// pattern is stable once what remains repeats.
+
+// Stabilization describes how a pattern settled down.
+type Stabilization struct {
+	// Stable is whether the pattern settled down within the generations it was run for.
+	Stable bool `json:"stable"`
+
+	// Generation is the first generation after which the pattern, less escaping spaceships, repeats: its lifespan.
+	// Period is how often it repeats from then on.
+	Generation int `json:"generation"`
+	Period     int `json:"period"`
+
+	// Population is the number of living cells at that generation, counting escaping spaceships as they were when
+	// taken off the field.
+	Population int `json:"population"`
+
+	// Ships is the number of escaping spaceships, by name.
+	Ships map[string]int `json:"ships"`
+}
+
+// String describes how the pattern settled, such as "stabilizes at generation 1103 with period 2, population 116, and 6
+// escaping gliders".
+func (s Stabilization) String() string {
+	if !s.Stable {
+		return "doesn't stabilize"
+	}
+	var ships []string
+	names := make([]string, 0, len(s.Ships))
+	for name := range s.Ships {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		plural := "s"
+		if s.Ships[name] == 1 {
+			plural = ""
+		}
+		ships = append(ships, fmt.Sprintf("%d escaping %s%s", s.Ships[name], name, plural))
+	}
+	if len(ships) == 0 {
+		ships = []string{"no escaping spaceships"}
+	}
+	return fmt.Sprintf("stabilizes at generation %d with period %d, population %d, and %s",
+		s.Generation, s.Period, s.Population, strings.Join(ships, ", "))
+}
+
+// lane is how close anything else may come to a spaceship, now or in the future, for it to count as escaping. This is
+// measured between their middles, plus half the size of the spaceship.
+const lane = 8
+
+// A body is an object on the field along with where it is heading: a known spaceship, or anything else, which is
+// taken to stay put.
+type body struct {
+	points []point
+	ship   shape
+	moving bool
+
+	// x and y are the middle of the object, and vx and vy how far it moves each generation.
+	x, y, vx, vy float64
+	size         float64
+}
+
+// newBody works out where an object is and where it is heading.
+func newBody(object []point) body {
+	key, left, top := shapeKey(object)
+	_, _, width, height := bounds(object)
+	b := body{points: object, x: float64(left) + float64(width)/2, y: float64(top) + float64(height)/2}
+	b.size = math.Max(float64(width), float64(height))
+	if s, ok := shapes[key]; ok && (s.dx != 0 || s.dy != 0) {
+		b.ship, b.moving = s, true
+		b.vx, b.vy = float64(s.dx)/float64(s.period), float64(s.dy)/float64(s.period)
+	}
+	return b
+}
+
+// approach returns how close two things at the given positions relative to one another will come, given how fast
+// the second is moving relative to the first.
+func approach(rx, ry, vx, vy float64) float64 {
+	t := 0.0
+	if speed2 := vx*vx + vy*vy; speed2 > 0 {
+		t = math.Max(0, -(rx*vx+ry*vy)/speed2)
+	}
+	return math.Hypot(rx+vx*t, ry+vy*t)
+}
+
+// escaping returns whether the given body is a spaceship which nothing else on the field will ever come near. Other
+// spaceships are followed along their paths, and every cell of anything else is taken to stay where it is.
+func escaping(i int, bodies []body, f *rle.RLEField) bool {
+	b := bodies[i]
+	if !b.moving {
+		return false
+	}
+	wrap := func(d float64, size int) float64 {
+		return d - float64(size)*math.Round(d/float64(size))
+	}
+	for j, other := range bodies {
+		if j == i {
+			continue
+		}
+		if other.moving {
+			rx, ry := wrap(other.x-b.x, f.Width), wrap(other.y-b.y, f.Height)
+			if approach(rx, ry, other.vx-b.vx, other.vy-b.vy) < lane+(b.size+other.size)/2 {
+				return false
+			}
+			continue
+		}
+		for _, p := range other.points {
+			rx, ry := wrap(float64(p.x)+0.5-b.x, f.Width), wrap(float64(p.y)+0.5-b.y, f.Height)
+			if approach(rx, ry, -b.vx, -b.vy) < lane+b.size/2 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Stabilize runs the model for up to the given number of generations, taking escaping spaceships off the field as it
+// goes, until what remains repeats. Spaceships are found by their shapes under Conway's Game of Life, and are taken
+// off using ToggleCell, so this suits two-state engines following Life or similar rules.
+func Stabilize(m base.Model, generations int) Stabilization {
+	return settle(m, generations, 1)
+}
+
+// settle is Stabilize, but only looks for escaping spaceships and repeats once every so many generations, which
+// saves exporting and breaking up the field every generation. The generation and period it finds are then multiples
+// of that, rather than exact, but the pattern is left the same once it settles.
+func settle(m base.Model, generations, every int) Stabilization {
+	result := Stabilization{Ships: map[string]int{}}
+	shipCells := 0
+	history := map[uint64]int{}
+	for gen := 0; gen <= generations; gen++ {
+		if gen > 0 {
+			m.Next()
+		}
+		if gen%every != 0 {
+			continue
+		}
+		f := m.Export()
+		var bodies []body
+		for _, object := range components(f, 2) {
+			bodies = append(bodies, newBody(object))
+		}
+		for i, b := range bodies {
+			if !escaping(i, bodies, f) {
+				continue
+			}
+			for _, p := range b.points {
+				x, y := (p.x%f.Width+f.Width)%f.Width, (p.y%f.Height+f.Height)%f.Height
+				m.ToggleCell(x, y)
+				f.Field[y][x] = false
+			}
+			result.Ships[b.ship.name]++
+			shipCells += len(b.points)
+		}
+
+		key, left, top, _, _ := Normalize(f)
+		h := fnv.New64a()
+		h.Write([]byte{byte(left), byte(left >> 8), byte(top), byte(top >> 8)})
+		h.Write([]byte(key))
+		sum := h.Sum64()
+		if first, ok := history[sum]; ok {
+			result.Stable = true
+			result.Generation = first
+			result.Period = gen - first
+			result.Population = population(f) + shipCells
+			return result
+		}
+		history[sum] = gen
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package analysis
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/isotropic"
+	"github.com/makyo/gogol/rle"
+)
+
+// stabilize runs a pattern until it settles on a field of the given size.
+func stabilize(pattern string, size, generations int) Stabilization {
+	f, err := rle.Unmarshal(pattern)
+	So(err, ShouldBeNil)
+	m := isotropic.New(size, size)
+	if f.Rule != "" {
+		So(m.SetRule(f.Rule), ShouldBeNil)
+	}
+	m.Ingest(f)
+	return Stabilize(m, generations)
+}
+
+func TestStabilize(t *testing.T) {
+	Convey("The R-pentomino should settle at generation 1103, leaving 116 cells, six of them in escaping gliders", t, func() {
+		s := stabilize("x = 3, y = 3\nb2o$2o$bo!", 200, 2000)
+		So(s.Stable, ShouldBeTrue)
+		So(s.Generation, ShouldEqual, 1103)
+		So(s.Period, ShouldEqual, 2)
+		So(s.Population, ShouldEqual, 116)
+		So(s.Ships, ShouldResemble, map[string]int{"glider": 6})
+		So(s.String(), ShouldEqual, "stabilizes at generation 1103 with period 2, population 116, and 6 escaping gliders")
+	})
+
+	Convey("Spaceships should escape whichever way they're headed", t, func() {
+		s := stabilize("x = 22, y = 8\n20bo$21bo$19b3o2$bo2bo$o$o3bo$4o!", 64, 200)
+		So(s.Stable, ShouldBeTrue)
+		So(s.Population, ShouldEqual, 14)
+		So(s.Ships, ShouldResemble, map[string]int{"glider": 1, "lightweight spaceship": 1})
+	})
+
+	Convey("A glider heading into a block shouldn't be counted as escaping", t, func() {
+		s := stabilize("x = 12, y = 12\nbo$2bo$3o8$10b2o$10b2o!", 64, 200)
+		So(s.Stable, ShouldBeTrue)
+		So(s.Ships, ShouldBeEmpty)
+	})
+
+	Convey("Patterns which never settle down should say so", t, func() {
+		s := stabilize("x = 1, y = 1, rule = B1/S012345678\no!", 64, 20)
+		So(s.Stable, ShouldBeFalse)
+		So(s.String(), ShouldEqual, "doesn't stabilize")
+	})
+
+	Convey("Looking only every so often should leave the same cells and escaping spaceships behind", t, func() {
+		f, _ := rle.Unmarshal("x = 3, y = 3\nb2o$2o$bo!")
+		each, often := isotropic.New(200, 200), isotropic.New(200, 200)
+		each.Ingest(f)
+		often.Ingest(f)
+		exact := Stabilize(each, 2000)
+		s := settle(often, 2000, searchEvery)
+		So(s.Stable, ShouldBeTrue)
+		So(s.Generation, ShouldBeGreaterThanOrEqualTo, exact.Generation)
+		So(s.Generation%searchEvery, ShouldEqual, 0)
+		So(s.Ships, ShouldResemble, exact.Ships)
+		So(TakeCensus(often), ShouldResemble, TakeCensus(each))
+	})
+
+	Convey("Patterns which die should settle with nothing left", t, func() {
+		s := stabilize("x = 3, y = 1\n2o!", 20, 20)
+		So(s.Stable, ShouldBeTrue)
+		So(s.Population, ShouldEqual, 0)
+		So(s.String(), ShouldEqual, "stabilizes at generation 1 with period 1, population 0, and no escaping spaceships")
+	})
+}
+
+func TestShapes(t *testing.T) {
+	Convey("Every phase and orientation of the standard spaceships should be known, with their speeds", t, func() {
+		count := map[string]int{}
+		for _, s := range shapes {
+			if s.dx == 0 && s.dy == 0 {
+				continue
+			}
+			count[s.name]++
+			So(s.period, ShouldEqual, 4)
+			switch s.name {
+			case "glider":
+				So([]int{abs(s.dx), abs(s.dy)}, ShouldResemble, []int{1, 1})
+			default:
+				So(abs(s.dx)+abs(s.dy), ShouldEqual, 2)
+				So(s.dx*s.dy, ShouldEqual, 0)
+			}
+		}
+		So(count, ShouldResemble, map[string]int{
+			"glider":                 16,
+			"lightweight spaceship":  16,
+			"middleweight spaceship": 16,
+			"heavyweight spaceship":  16,
+		})
+	})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/makyo/gogol/analysis"
+	"github.com/makyo/gogol/base"
+)
+
+// analysisFlags adds the flags shared by the subcommands which run a single pattern to find out about it, returning a
+// function which starts the model once the flags are parsed. Fields sized 0 fit the pattern with the given number of
+// cells to spare.
+func analysisFlags(fs *flag.FlagSet) func(room int) (base.Model, error) {
+	algo := newAlgoFlags(fs, "isotropic")
+	width := fs.Int("width", 0, "Width of the field in cells (0 fits the pattern with room to spare)")
+	height := fs.Int("height", 0, "Height of the field in cells (0 fits the pattern with room to spare)")
+	return func(room int) (base.Model, error) {
+		if fs.NArg() != 1 {
+			return nil, fmt.Errorf("Usage: gogol %s [flags] pattern.rle", fs.Name())
+		}
+		imports, err := algo.image()
+		if err != nil {
+			return nil, err
+		}
+		f, err := loadPattern(fs.Arg(0), imports)
+		if err != nil {
+			return nil, err
+		}
+		w, h := *width, *height
+		if w == 0 {
+			w = f.Width + room
+		}
+		if h == 0 {
+			h = f.Height + room
+		}
+		return algo.start(w, h, 0, fs.Args())
+	}
+}
+
+// writeJSON writes a value to standard output as JSON.
+func writeJSON(v interface{}) error {
+	return json.NewEncoder(os.Stdout).Encode(v)
+}
+
+// analyzeCommand runs a pattern until it repeats and reports its period, displacement, and speed.
+//
+//	gogol analyze [flags] pattern.rle
+func analyzeCommand(args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	generations := fs.Int("generations", 1000, "Most generations to run before giving up")
+	asJSON := fs.Bool("json", false, "Write the result as JSON")
+	start := analysisFlags(fs)
+	fs.Parse(args)
+
+	// Start on a small field, which is quick to run, and only move to a larger one if the pattern spreads too far
+	// across it, up to enough room for it to spread one cell a generation without letting the field get out of hand.
+	limit := *generations
+	if limit > 1024 {
+		limit = 1024
+	}
+	var r analysis.Result
+	width, height := 0, 0
+	for room := 64; ; room *= 2 {
+		if room > limit {
+			room = limit
+		}
+		m, err := start(room)
+		if err != nil {
+			return err
+		}
+		f := m.Export()
+		if f.Width == width && f.Height == height {
+			// The field was sized by flags, so a larger one isn't on offer.
+			break
+		}
+		width, height = f.Width, f.Height
+		r = analysis.Period(m, *generations)
+		if !r.Cramped || room == limit {
+			break
+		}
+	}
+
+	if *asJSON {
+		return writeJSON(struct {
+			analysis.Result
+			Description string `json:"description"`
+			Speed       string `json:"speed,omitempty"`
+			Direction   string `json:"direction,omitempty"`
+		}{r, r.String(), r.Speed(), r.Direction()})
+	}
+	fmt.Println(r)
+	if r.Period > 0 {
+		fmt.Printf("period %d, displacement (%d, %d), from generation %d, population %d\n", r.Period, r.DX, r.DY, r.Start, r.Population)
+	}
+	return nil
+}
+
+// lifespanCommand runs a pattern until what remains of it, less escaping spaceships, settles down, and reports when.
+//
+//	gogol lifespan [flags] pattern.rle
+func lifespanCommand(args []string) error {
+	fs := flag.NewFlagSet("lifespan", flag.ExitOnError)
+	generations := fs.Int("generations", 50000, "Most generations to run before giving up")
+	asJSON := fs.Bool("json", false, "Write the result as JSON")
+	start := analysisFlags(fs)
+	fs.Parse(args)
+
+	// Escaping spaceships are taken off the field, so there only needs to be room for what they leave behind.
+	m, err := start(512)
+	if err != nil {
+		return err
+	}
+	s := analysis.Stabilize(m, *generations)
+
+	if *asJSON {
+		return writeJSON(s)
+	}
+	fmt.Println(s)
+	return nil
+}
+
+// censusCommand counts the objects in a pattern, optionally after running it for a while or until it settles.
+//
+//	gogol census [flags] pattern.rle
+func censusCommand(args []string) error {
+	fs := flag.NewFlagSet("census", flag.ExitOnError)
+	generations := fs.Int("generations", 0, "Number of generations to run before counting, or the most to run with -settle")
+	settle := fs.Bool("settle", false, "Run until the pattern settles down, counting escaping spaceships along with what's left")
+	asJSON := fs.Bool("json", false, "Write the census as JSON")
+	start := analysisFlags(fs)
+	fs.Parse(args)
+
+	m, err := start(512)
+	if err != nil {
+		return err
+	}
+	var ships map[string]int
+	if *settle {
+		if *generations == 0 {
+			*generations = 50000
+		}
+		ships = analysis.Stabilize(m, *generations).Ships
+	} else {
+		for i := 0; i < *generations; i++ {
+			m.Next()
+		}
+	}
+	c := analysis.TakeCensus(m)
+	for name, count := range ships {
+		c.Objects[name] += count
+	}
+
+	if *asJSON {
+		return writeJSON(c)
+	}
+	fmt.Println(c)
+	return nil
+}
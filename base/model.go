@@ -7,5 +7,74 @@ type Model interface {
 	Populate()
 	ToggleCell(int, int)
 	Ingest(*rle.RLEField)
+	Export() *rle.RLEField
 	String() string
 }
+
+// Ruled is implemented by models which can follow rules other than those of Conway's Game of Life.
+type Ruled interface {
+	SetRule(string) error
+}
+
+// Wide is implemented by models whose String uses more than one column of the terminal to draw each cell.
+type Wide interface {
+	CellWidth() int
+}
+
+// Sheared is implemented by models whose String draws each row of cells moved sideways from the one above, such as
+// hexagonal grids drawn on a square one. RowShift returns how many columns the given row is moved to the right,
+// wrapping around the width of the field on screen.
+type Sheared interface {
+	RowShift(y int) int
+}
+
+// Seedable is implemented by models which can start from a single living cell instead of a random field.
+type Seedable interface {
+	PopulateSingle()
+}
+
+// Reversible is implemented by models which can be run backwards.
+type Reversible interface {
+	Reverse() error
+}
+
+// Layered is implemented by models whose fields have more than one layer, only one of which is drawn at a time.
+type Layered interface {
+	MoveLayer(int)
+	ToggleProjection()
+}
+
+// Volumetric is implemented by models whose fields are three-dimensional, which can read and write whole fields as
+// RLE3 as well as a layer at a time as RLE.
+type Volumetric interface {
+	Ingest3(*rle.RLE3Field)
+	Export3() *rle.RLE3Field
+}
+
+// Diagrammed is implemented by models whose String draws more than the field as it stands, such as one-dimensional
+// automata drawing each generation beneath the last. Diagram returns what is drawn, while Export returns only the
+// current field.
+type Diagrammed interface {
+	Diagram() *rle.RLEField
+}
+
+// Local is implemented by models which can work out the next state of a single cell from the field as it stands, which
+// lets them be updated a cell at a time rather than all at once.
+type Local interface {
+	Size() (int, int)
+	State(x, y int) int
+	SetState(x, y, state int)
+	NextState(x, y int) int
+}
+
+// Neighbors returns the positions of the eight cells around the given one in a field of the given size stored row by
+// row, wrapping around the edges: the three above it from left to right, the ones to its left and right, then the
+// three below it.
+func Neighbors(x, y, width, height int) [8]int {
+	up := ((y + height - 1) % height) * width
+	row := y * width
+	down := ((y + 1) % height) * width
+	left := (x + width - 1) % width
+	right := (x + 1) % width
+	return [8]int{up + left, up + x, up + right, row + left, row + right, down + left, down + x, down + right}
+}
@@ -0,0 +1,197 @@
+package colorlife
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand"
+	"strings"
+
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/render"
+	"github.com/makyo/gogol/rle"
+)
+
+// Immigration and QuadLife follow the rules of Conway's Game of Life, but every living cell has one of several colors.
+// Living cells keep their color for as long as they survive, while newborn cells take the color held by most of their
+// three parents. In QuadLife, if the parents are all different colors, the newborn takes the fourth color instead.
+//
+// Each cell is a single byte holding its state: 0 for dead, and 1 up to the number of colors for a living cell of that
+// color, which is how Golly numbers them too.
+
+const dead = 0
+
+// rules names the rules this model can follow, along with the colors used to draw each state.
+var rules = map[string][]color.Color{
+	"Immigration": {
+		nil,
+		color.RGBA{0xff, 0x40, 0x40, 0xff},
+		color.RGBA{0x40, 0x80, 0xff, 0xff},
+	},
+	"QuadLife": {
+		nil,
+		color.RGBA{0xff, 0x40, 0x40, 0xff},
+		color.RGBA{0x40, 0xd0, 0x40, 0xff},
+		color.RGBA{0x40, 0x80, 0xff, 0xff},
+		color.RGBA{0xff, 0xd0, 0x00, 0xff},
+	},
+}
+
+type model struct {
+	width  int
+	height int
+	field  []byte
+	rule   string
+	colors []color.Color
+}
+
+// parents returns the number of living neighbors around the given cell, and how many of them have each color.
+func (m *model) parents(x, y int) (int, [5]int) {
+	count := 0
+	var colors [5]int
+	for _, pos := range base.Neighbors(x, y, m.width, m.height) {
+		if m.field[pos] != dead {
+			count++
+			colors[m.field[pos]]++
+		}
+	}
+	return count, colors
+}
+
+// nextState returns the state the given cell will have in the next generation.
+func (m *model) nextState(x, y int) byte {
+	state := m.field[y*m.width+x]
+	count, colors := m.parents(x, y)
+	switch {
+	case state != dead && (count == 2 || count == 3):
+		return state
+	case state == dead && count == 3:
+		return m.birth(colors)
+	}
+	return dead
+}
+
+// Next evolves the field one generation.
+func (m *model) Next() {
+	next := make([]byte, len(m.field))
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			next[y*m.width+x] = m.nextState(x, y)
+		}
+	}
+	m.field = next
+}
+
+// Size returns the width and height of the field.
+func (m *model) Size() (int, int) {
+	return m.width, m.height
+}
+
+// State returns the state of the given cell.
+func (m *model) State(x, y int) int {
+	return int(m.field[y*m.width+x])
+}
+
+// SetState sets the state of the given cell.
+func (m *model) SetState(x, y, state int) {
+	m.field[y*m.width+x] = byte(state)
+}
+
+// NextState returns the state the given cell would have after the next generation, given its neighbors as they stand.
+func (m *model) NextState(x, y int) int {
+	return int(m.nextState(x, y))
+}
+
+// birth returns the color of a cell born to three parents, given how many of them have each color.
+func (m *model) birth(colors [5]int) byte {
+	var missing byte
+	for c := byte(1); c < byte(len(m.colors)); c++ {
+		if colors[c] >= 2 {
+			return c
+		}
+		if colors[c] == 0 {
+			missing = c
+		}
+	}
+
+	// Only QuadLife has enough colors for all three parents to be different.
+	return missing
+}
+
+// Populate generates a random field of automata, where each cell has a 1 in 5 chance of being alive, with each color
+// equally likely.
+func (m *model) Populate() {
+	for i, _ := range m.field {
+		m.field[i] = dead
+		if rand.Intn(5) == 0 {
+			m.field[i] = byte(1 + rand.Intn(len(m.colors)-1))
+		}
+	}
+}
+
+// SetRule sets the rule the model follows, either Immigration or QuadLife. Any cells in colors which the new rule
+// doesn't have are killed.
+func (m *model) SetRule(rulestring string) error {
+	for name, colors := range rules {
+		if strings.EqualFold(strings.TrimSpace(rulestring), name) {
+			m.rule = name
+			m.colors = colors
+			for i, c := range m.field {
+				if int(c) >= len(colors) {
+					m.field[i] = dead
+				}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("This algorithm only supports Immigration or QuadLife, not %q", rulestring)
+}
+
+// Ingest sets the field to the given value. Cells in two-state patterns take the first color.
+func (m *model) Ingest(f *rle.RLEField) {
+	startX := (m.width - f.Width) / 2
+	startY := (m.height - f.Height) / 2
+	for y, row := range f.Field {
+		for x, _ := range row {
+			state := f.State(x, y)
+			if state == dead || state >= len(m.colors) {
+				continue
+			}
+			m.field[((y+startY+m.height)%m.height)*m.width+(x+startX+m.width)%m.width] = byte(state)
+		}
+	}
+}
+
+// ToggleCell cycles the given cell from dead through each of the colors, and back to dead.
+func (m *model) ToggleCell(x, y int) {
+	pos := y*m.width + x
+	m.field[pos] = byte((int(m.field[pos]) + 1) % len(m.colors))
+}
+
+// Export returns the current state of the field, including the color of each cell.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(m.rule)
+	for i, c := range m.field {
+		f.SetState(i%m.width, i/m.width, int(c))
+	}
+	return f
+}
+
+// String builds the entire screen's worth of cells to be printed by returning a • for each living cell, in its color,
+// or a space for a dead cell.
+func (m *model) String() string {
+	return render.ColorRuns(m.field, m.width, func(state byte) color.Color {
+		return m.colors[state]
+	})
+}
+
+// New creates a model of the given size following Immigration.
+func New(width, height int) *model {
+	return &model{
+		width:  width,
+		height: height,
+		field:  make([]byte, width*height),
+		rule:   "Immigration",
+		colors: rules["Immigration"],
+	}
+}
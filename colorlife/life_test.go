@@ -0,0 +1,80 @@
+package colorlife
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/isotropic"
+	"github.com/makyo/gogol/rle"
+)
+
+func TestNext(t *testing.T) {
+	Convey("Under Immigration, a newborn cell should take the color of most of its parents", t, func() {
+		f, err := rle.Unmarshal(`x = 3, y = 3, rule = Immigration
+A.B$3.$2.A!`)
+		So(err, ShouldBeNil)
+		m := New(5, 5)
+		So(m.SetRule(f.Rule), ShouldBeNil)
+		m.Ingest(f)
+		m.Next()
+		So(m.field[2*5+2], ShouldEqual, 1)
+	})
+
+	Convey("Under QuadLife, a newborn cell with parents of three colors should take the fourth", t, func() {
+		f, err := rle.Unmarshal(`x = 3, y = 3, rule = QuadLife
+A.B$3.$2.D!`)
+		So(err, ShouldBeNil)
+		m := New(5, 5)
+		So(m.SetRule(f.Rule), ShouldBeNil)
+		m.Ingest(f)
+		So(m.rule, ShouldEqual, "QuadLife")
+		m.Next()
+		So(m.field[2*5+2], ShouldEqual, 3)
+	})
+
+	Convey("Ignoring color, both rules should match Conway's Game of Life", t, func() {
+		for _, rule := range []string{"Immigration", "QuadLife"} {
+			m := New(32, 32)
+			m.SetRule(rule)
+			rand.Seed(1)
+			m.Populate()
+			life := isotropic.New(32, 32)
+			life.Ingest(m.Export())
+			for i := 0; i < 20; i++ {
+				m.Next()
+				life.Next()
+			}
+			So(m.Export().Field, ShouldResemble, life.Export().Field)
+		}
+	})
+}
+
+func TestModel(t *testing.T) {
+	Convey("Exporting the field should keep the color of every cell", t, func() {
+		m := New(16, 16)
+		m.SetRule("quadlife")
+		m.Populate()
+		f, err := rle.Unmarshal(m.Export().Marshal())
+		So(err, ShouldBeNil)
+		So(f.Rule, ShouldEqual, "QuadLife")
+		for i, c := range m.field {
+			So(f.State(i%16, i/16), ShouldEqual, int(c))
+		}
+	})
+
+	Convey("Toggling a cell should cycle through every color", t, func() {
+		m := New(1, 1)
+		var states []byte
+		for i := 0; i < 3; i++ {
+			m.ToggleCell(0, 0)
+			states = append(states, m.field[0])
+		}
+		So(states, ShouldResemble, []byte{1, 2, 0})
+	})
+
+	Convey("Other rules should be rejected", t, func() {
+		So(New(1, 1).SetRule("B3/S23"), ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,149 @@
+package continuous
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// Convolving a field with a kernel directly takes time in proportion to the size of the kernel for every cell, which
+// gets slow for kernels more than a dozen or so cells across. Instead, the field and kernel can be taken through a
+// Fourier transform, where convolution becomes multiplication, and back again. Since the transform wraps around, this
+// also gives the convolution on a torus for free.
+//
+// The field can be any size, so lengths which aren't powers of two are transformed using Bluestein's algorithm, which
+// turns a transform of any length into a convolution which can be done with transforms whose lengths are powers of two.
+
+// A plan holds everything which can be worked out ahead of time for transforms of a single length.
+type plan struct {
+	n int
+
+	// For lengths which aren't powers of two, size is the power of two used for Bluestein's algorithm, chirp holds
+	// the factors exp(-iπk²/n), and filter holds the transform of their conjugates.
+	size   int
+	chirp  []complex128
+	filter []complex128
+}
+
+// newPlan creates a plan for transforms of length n.
+func newPlan(n int) *plan {
+	p := &plan{n: n}
+	if n&(n-1) == 0 {
+		return p
+	}
+	p.size = 1 << bits.Len(uint(2*n-1))
+	p.chirp = make([]complex128, n)
+	p.filter = make([]complex128, p.size)
+	for k := 0; k < n; k++ {
+		// k² can overflow the precision of the angle for long lengths, so it's taken modulo 2n first.
+		angle := math.Pi * float64((k*k)%(2*n)) / float64(n)
+		p.chirp[k] = cmplx.Rect(1, -angle)
+		p.filter[k] = cmplx.Conj(p.chirp[k])
+		if k > 0 {
+			p.filter[p.size-k] = p.filter[k]
+		}
+	}
+	radix2(p.filter, false)
+	return p
+}
+
+// transform replaces a, which must have the length of the plan, with its Fourier transform, or its inverse. The inverse
+// is not scaled, so it must be divided by the length to undo a transform.
+func (p *plan) transform(a []complex128, inverse bool) {
+	if p.chirp == nil {
+		radix2(a, inverse)
+		return
+	}
+
+	// The inverse transform is the forward transform with the input and output conjugated.
+	if inverse {
+		for i := range a {
+			a[i] = cmplx.Conj(a[i])
+		}
+	}
+	work := make([]complex128, p.size)
+	for k := range a {
+		work[k] = a[k] * p.chirp[k]
+	}
+	radix2(work, false)
+	for i := range work {
+		work[i] *= p.filter[i]
+	}
+	radix2(work, true)
+	scale := complex(1/float64(p.size), 0)
+	for k := range a {
+		a[k] = work[k] * scale * p.chirp[k]
+		if inverse {
+			a[k] = cmplx.Conj(a[k])
+		}
+	}
+}
+
+// radix2 replaces a, whose length must be a power of two, with its unscaled Fourier transform or inverse, using the
+// iterative Cooley-Tukey algorithm.
+func radix2(a []complex128, inverse bool) {
+	n := len(a)
+	if n < 2 {
+		return
+	}
+
+	// Put the elements in bit-reversed order, so that each pass can work in place.
+	shift := 64 - bits.Len(uint(n-1))
+	for i := range a {
+		j := int(bits.Reverse64(uint64(i)) >> shift)
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Rect(1, sign*2*math.Pi/float64(size))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := a[start+k], a[start+k+size/2]*w
+				a[start+k] = even + odd
+				a[start+k+size/2] = even - odd
+				w *= step
+			}
+		}
+	}
+}
+
+// fft2 holds the plans for two-dimensional transforms of a field of a given size.
+type fft2 struct {
+	width, height int
+	rows, cols    *plan
+}
+
+func newFFT2(width, height int) *fft2 {
+	return &fft2{width: width, height: height, rows: newPlan(width), cols: newPlan(height)}
+}
+
+// transform replaces a, which holds a field row by row, with its two-dimensional Fourier transform or its inverse. The
+// inverse is scaled, so that it undoes a transform.
+func (f *fft2) transform(a []complex128, inverse bool) {
+	for y := 0; y < f.height; y++ {
+		f.rows.transform(a[y*f.width:(y+1)*f.width], inverse)
+	}
+	col := make([]complex128, f.height)
+	for x := 0; x < f.width; x++ {
+		for y := range col {
+			col[y] = a[y*f.width+x]
+		}
+		f.cols.transform(col, inverse)
+		for y := range col {
+			a[y*f.width+x] = col[y]
+		}
+	}
+	if inverse {
+		scale := complex(1/float64(f.width*f.height), 0)
+		for i := range a {
+			a[i] *= scale
+		}
+	}
+}
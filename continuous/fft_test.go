@@ -0,0 +1,59 @@
+package continuous
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// dft is the Fourier transform done the slow way, straight from its definition.
+func dft(a []complex128) []complex128 {
+	result := make([]complex128, len(a))
+	for k := range result {
+		for j, v := range a {
+			result[k] += v * cmplx.Rect(1, -2*math.Pi*float64(j*k)/float64(len(a)))
+		}
+	}
+	return result
+}
+
+func TestFFT(t *testing.T) {
+	Convey("Transforms of any length should match the definition, and be undone by their inverse", t, func() {
+		for _, n := range []int{1, 2, 3, 5, 8, 12, 17, 64, 100} {
+			a := make([]complex128, n)
+			for i := range a {
+				a[i] = complex(rand.Float64(), rand.Float64())
+			}
+			expected := dft(a)
+			p := newPlan(n)
+			b := append([]complex128{}, a...)
+			p.transform(b, false)
+			for i := range b {
+				So(cmplx.Abs(b[i]-expected[i]), ShouldBeLessThan, 1e-9)
+			}
+			p.transform(b, true)
+			for i := range b {
+				So(cmplx.Abs(b[i]/complex(float64(n), 0)-a[i]), ShouldBeLessThan, 1e-9)
+			}
+		}
+	})
+
+	Convey("Convolving by way of Fourier transforms should match convolving directly", t, func() {
+		for _, rule := range []string{"Lenia:R=5,b=1,1/3", "SmoothLife:ra=6,ri=2"} {
+			m := New(30, 17)
+			So(m.SetRule(rule), ShouldBeNil)
+			m.Populate()
+			So(len(m.rule.kernels[len(m.rule.kernels)-1].weights), ShouldBeGreaterThan, fftThreshold)
+			for i, k := range m.rule.kernels {
+				direct := m.convolve(k)
+				transformed := m.convolveAll()[i]
+				for j := range direct {
+					So(math.Abs(float64(direct[j]-transformed[j])), ShouldBeLessThan, 1e-5)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package continuous
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A parameterSet is a single entry in a Lenia JSON file, such as the animals.json that comes with Lenia, which holds a
+// list of these. Only the parameters are used; the cells are left out.
+type parameterSet struct {
+	Code   string                     `json:"code"`
+	Name   string                     `json:"name"`
+	Params map[string]json.RawMessage `json:"params"`
+}
+
+// LoadParameters reads a Lenia rule from a JSON file holding either a single parameter set or a list of them. If there
+// is a list, the one whose name or code matches the given name is used, or the first one if no name is given.
+func LoadParameters(path, name string) (*Rule, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var sets []parameterSet
+	if strings.HasPrefix(strings.TrimSpace(string(contents)), "[") {
+		err = json.Unmarshal(contents, &sets)
+	} else {
+		sets = make([]parameterSet, 1)
+		err = json.Unmarshal(contents, &sets[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Malformed parameters - %v: %s", err, path)
+	}
+
+	for _, set := range sets {
+		if set.Params == nil || name != "" && !strings.EqualFold(name, set.Name) && !strings.EqualFold(name, set.Code) {
+			continue
+		}
+
+		// Lenia writes some parameters as numbers and others, such as b, as strings, so take them as they come.
+		var parts []string
+		for key, raw := range set.Params {
+			var value interface{}
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return nil, fmt.Errorf("Malformed parameters - %v: %s", err, path)
+			}
+			switch v := value.(type) {
+			case float64:
+				parts = append(parts, key+"="+strconv.FormatFloat(v, 'g', -1, 64))
+			case string:
+				parts = append(parts, key+"="+v)
+			case []interface{}:
+				var peaks []string
+				for _, peak := range v {
+					peaks = append(peaks, fmt.Sprint(peak))
+				}
+				parts = append(parts, key+"="+strings.Join(peaks, ","))
+			}
+		}
+		return ParseRule(Lenia + ":" + strings.Join(parts, ","))
+	}
+	return nil, fmt.Errorf("No parameters named %q: %s", name, path)
+}
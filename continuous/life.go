@@ -0,0 +1,210 @@
+package continuous
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/makyo/gogol/rle"
+)
+
+// Rather than being alive or dead, every cell holds a number from 0 to 1, and rather than counting neighbors, each
+// cell looks at a weighted average of everything within some radius, given by convolving the field with a kernel.
+
+// fftThreshold is the number of weights above which a kernel is convolved by way of Fourier transforms rather than
+// directly. Below it, the extra work of the transforms isn't worth it.
+const fftThreshold = 64
+
+type model struct {
+	width  int
+	height int
+	field  []float32
+	rule   *Rule
+
+	// The transforms are planned once for the size of the field, and the kernels transformed once for each rule.
+	fft     *fft2
+	spectra [][]complex128
+}
+
+// convolve returns the weighted sum of the field around each cell using the given kernel, wrapping around the edges.
+func (m *model) convolve(k kernel) []float32 {
+	result := make([]float32, len(m.field))
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			var sum float32
+			for _, w := range k.weights {
+				sum += w.w * m.field[((y+w.dy)%m.height+m.height)%m.height*m.width+((x+w.dx)%m.width+m.width)%m.width]
+			}
+			result[y*m.width+x] = sum
+		}
+	}
+	return result
+}
+
+// spectrum returns the Fourier transform of the given kernel laid out over the whole field, with its center at the
+// origin, transforming it the first time it's needed.
+func (m *model) spectrum(i int) []complex128 {
+	if m.spectra == nil {
+		m.spectra = make([][]complex128, len(m.rule.kernels))
+	}
+	if m.spectra[i] == nil {
+		s := make([]complex128, len(m.field))
+		for _, w := range m.rule.kernels[i].weights {
+			s[((w.dy%m.height)+m.height)%m.height*m.width+((w.dx%m.width)+m.width)%m.width] += complex(float64(w.w), 0)
+		}
+		m.fft.transform(s, false)
+		m.spectra[i] = s
+	}
+	return m.spectra[i]
+}
+
+// convolveAll returns the convolution of the field with each of the rule's kernels, using Fourier transforms for the
+// large ones. The kernels are all symmetric, so convolving with them is the same as the weighted sums above.
+func (m *model) convolveAll() [][]float32 {
+	results := make([][]float32, len(m.rule.kernels))
+	var field []complex128
+	for i, k := range m.rule.kernels {
+		if len(k.weights) <= fftThreshold {
+			results[i] = m.convolve(k)
+			continue
+		}
+		if field == nil {
+			field = make([]complex128, len(m.field))
+			for j, v := range m.field {
+				field[j] = complex(float64(v), 0)
+			}
+			m.fft.transform(field, false)
+		}
+		spectrum := m.spectrum(i)
+		product := make([]complex128, len(field))
+		for j := range product {
+			product[j] = field[j] * spectrum[j]
+		}
+		m.fft.transform(product, true)
+		results[i] = make([]float32, len(m.field))
+		for j, v := range product {
+			results[i][j] = float32(real(v))
+		}
+	}
+	return results
+}
+
+// Next evolves the field one step.
+func (m *model) Next() {
+	potentials := m.convolveAll()
+	next := make([]float32, len(m.field))
+	p := make([]float32, len(potentials))
+	for i, state := range m.field {
+		for j := range potentials {
+			p[j] = potentials[j][i]
+		}
+		next[i] = m.rule.update(state, p)
+	}
+	m.field = next
+}
+
+// Populate fills the field with random noise, where each cell has an even chance of being empty or holding a random
+// value.
+func (m *model) Populate() {
+	for i := range m.field {
+		m.field[i] = 0
+		if rand.Intn(2) == 0 {
+			m.field[i] = rand.Float32()
+		}
+	}
+}
+
+// SetRule sets the rule the model follows from a rulestring such as Lenia:R=13,m=0.15,s=0.015 (see ParseRule), or
+// from a Lenia JSON file given as its path, optionally followed by a colon and the name of the parameter set to use,
+// such as animals.json:Orbium.
+func (m *model) SetRule(rulestring string) error {
+	var r *Rule
+	var err error
+	if path, name, _ := strings.Cut(rulestring, ":"); strings.HasSuffix(strings.ToLower(path), ".json") {
+		r, err = LoadParameters(path, name)
+	} else {
+		r, err = ParseRule(rulestring)
+	}
+	if err != nil {
+		return err
+	}
+	m.rule = r
+	m.spectra = nil
+	return nil
+}
+
+// Ingest sets the field to the given value. Cells in two-state patterns are set to 1, and cells in multi-state
+// patterns to their state out of 255, as Lenia saves them.
+func (m *model) Ingest(f *rle.RLEField) {
+	startX := (m.width - f.Width) / 2
+	startY := (m.height - f.Height) / 2
+	for y, row := range f.Field {
+		for x, _ := range row {
+			state := f.State(x, y)
+			if state == 0 {
+				continue
+			}
+			value := float32(1)
+			if f.States != nil {
+				value = float32(math.Min(float64(state), 255)) / 255
+			}
+			m.field[((y+startY+m.height)%m.height)*m.width+(x+startX+m.width)%m.width] = value
+		}
+	}
+}
+
+// ToggleCell paints a disc a quarter of the size of the first kernel around the given cell, or clears it if the cell
+// is already more than half full.
+func (m *model) ToggleCell(x, y int) {
+	value := float32(1)
+	if m.field[y*m.width+x] > 0.5 {
+		value = 0
+	}
+	radius := m.rule.kernels[0].radius / 4
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			if dx*dx+dy*dy <= radius*radius {
+				m.field[((y+dy)%m.height+m.height)%m.height*m.width+((x+dx)%m.width+m.width)%m.width] = value
+			}
+		}
+	}
+}
+
+// Export returns the current state of the field, with each cell's value scaled to a state from 0 to 255.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(m.rule.String())
+	for i, v := range m.field {
+		f.SetState(i%m.width, i/m.width, int(math.Round(float64(v)*255)))
+	}
+	return f
+}
+
+// shades are used to draw cells, from empty to full.
+var shades = []string{" ", "░", "▒", "▓", "█"}
+
+// String builds the entire screen's worth of cells to be printed by returning a shade block for each cell, darker or
+// lighter according to its value.
+func (m *model) String() string {
+	var frame strings.Builder
+	for i, v := range m.field {
+		if i > 0 && i%m.width == 0 {
+			frame.WriteString("\n")
+		}
+		shade := int(math.Round(float64(v) * float64(len(shades)-1)))
+		frame.WriteString(shades[shade])
+	}
+	return frame.String()
+}
+
+// New creates a model of the given size following Lenia with the parameters of Orbium.
+func New(width, height int) *model {
+	r, _ := ParseRule(Lenia + ":")
+	return &model{
+		width:  width,
+		height: height,
+		field:  make([]float32, width*height),
+		rule:   r,
+		fft:    newFFT2(width, height),
+	}
+}
@@ -0,0 +1,291 @@
+package continuous
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of rule.
+const (
+	// Lenia convolves the field with a single kernel made of one or more rings, and grows or shrinks each cell by how
+	// close the result is to some ideal (see: https://chakazul.github.io/lenia.html ).
+	Lenia = "Lenia"
+
+	// SmoothLife measures how full a disc around each cell and a ring around that are, and follows a smoothed-out
+	// version of Life's birth and survival rules (see: https://arxiv.org/abs/1111.1567 ).
+	SmoothLife = "SmoothLife"
+)
+
+// A kernel is a set of weights for the cells around a cell, which add up to one.
+type kernel struct {
+	radius  int
+	weights []weight
+}
+
+// A weight is the weight given to the cell at some offset.
+type weight struct {
+	dx, dy int
+	w      float32
+}
+
+// newKernel builds a kernel from a function giving the weight at each distance from the center, then scales it so
+// that the weights add up to one.
+func newKernel(radius int, f func(distance float64) float64) kernel {
+	k := kernel{radius: radius}
+	total := 0.0
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			w := f(math.Hypot(float64(dx), float64(dy)))
+			if w > 0 {
+				k.weights = append(k.weights, weight{dx, dy, float32(w)})
+				total += w
+			}
+		}
+	}
+	for i := range k.weights {
+		k.weights[i].w /= float32(total)
+	}
+	return k
+}
+
+// Rule is a continuous rule, made up of the kernels the field is convolved with and the function which gives each
+// cell's next state from its current state and the results.
+type Rule struct {
+	kind    string
+	params  map[string]string
+	kernels []kernel
+	update  func(state float32, potentials []float32) float32
+}
+
+// String returns the rule as a rulestring, with its parameters in order.
+func (r *Rule) String() string {
+	keys := make([]string, 0, len(r.params))
+	for key := range r.params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		keys[i] = key + "=" + r.params[key]
+	}
+	return r.kind + ":" + strings.Join(keys, ",")
+}
+
+// clip keeps a state between 0 and 1.
+func clip(state float64) float32 {
+	return float32(math.Max(0, math.Min(1, state)))
+}
+
+// Lenia's kernel core functions, giving the height of a ring at a distance from 0 to 1 across it.
+var cores = map[int]func(r float64) float64{
+	// Exponential.
+	1: func(r float64) float64 {
+		if r <= 0 || r >= 1 {
+			return 0
+		}
+		return math.Exp(4 - 1/(r*(1-r)))
+	},
+	// Polynomial.
+	2: func(r float64) float64 { return math.Pow(4*r*(1-r), 4) },
+	// Rectangular.
+	3: func(r float64) float64 {
+		if r >= 0.25 && r <= 0.75 {
+			return 1
+		}
+		return 0
+	},
+	// Staircase.
+	4: func(r float64) float64 {
+		if r >= 0.25 && r <= 0.75 {
+			return 1
+		}
+		return 0.5
+	},
+}
+
+// Lenia's growth functions, giving how much a cell grows (or, if negative, shrinks) for a potential u, given the ideal
+// potential m and the width around it s.
+var growths = map[int]func(u, m, s float64) float64{
+	// Exponential (Gaussian).
+	1: func(u, m, s float64) float64 { return 2*math.Exp(-(u-m)*(u-m)/(2*s*s)) - 1 },
+	// Polynomial.
+	2: func(u, m, s float64) float64 { return 2*math.Pow(math.Max(0, 1-(u-m)*(u-m)/(9*s*s)), 4) - 1 },
+	// Step.
+	3: func(u, m, s float64) float64 {
+		if math.Abs(u-m) <= s {
+			return 1
+		}
+		return -1
+	},
+}
+
+// defaults holds the parameters used for each kind of rule when they aren't given. Lenia's are those of Orbium, and
+// SmoothLife's are Rafler's, with a smaller radius to suit a terminal.
+var defaults = map[string]map[string]string{
+	Lenia:      {"R": "13", "T": "10", "b": "1", "m": "0.15", "s": "0.015", "kn": "1", "gn": "1"},
+	SmoothLife: {"ra": "10", "ri": "3.33", "b1": "0.278", "b2": "0.365", "d1": "0.267", "d2": "0.445", "an": "0.028", "am": "0.147", "dt": "0"},
+}
+
+// ParseRule parses a continuous rule, given as its kind followed by its parameters, such as
+// Lenia:R=13,T=10,b=1,m=0.15,s=0.015,kn=1,gn=1 or SmoothLife:ra=10,b1=0.278,b2=0.365,d1=0.267,d2=0.445. Any
+// parameters left out take their default values.
+//
+// Lenia's parameters are the radius of the kernel R, the number of steps per unit of time T, the heights of each ring
+// of the kernel b (as fractions, such as 1,1/2), the center m and width s of the growth function, and the kernel core
+// and growth functions kn and gn, both of which are 1 for the usual exponential ones.
+//
+// SmoothLife's parameters are the outer and inner radii ra and ri, the birth and death intervals b1 to b2 and d1 to d2,
+// the smoothness of the steps between them an and am, and the time step dt, where 0 means that each generation replaces
+// the last outright.
+func ParseRule(rulestring string) (*Rule, error) {
+	kind, spec, _ := strings.Cut(strings.TrimSpace(rulestring), ":")
+	r := &Rule{params: map[string]string{}}
+	for k, d := range defaults {
+		if strings.EqualFold(kind, k) {
+			r.kind = k
+			for key, value := range d {
+				r.params[key] = value
+			}
+		}
+	}
+	if r.kind == "" {
+		return nil, fmt.Errorf("Malformed rule - must be Lenia or SmoothLife, followed by ':' and parameters: %q", rulestring)
+	}
+
+	// Lenia's ring heights contain commas of their own, so anything without an = belongs to the parameter before it.
+	key := ""
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if k, value, found := strings.Cut(part, "="); found {
+			key = strings.TrimSpace(k)
+			if _, ok := r.params[key]; !ok {
+				return nil, fmt.Errorf("Malformed rule - unknown parameter %q: %q", key, rulestring)
+			}
+			r.params[key] = strings.TrimSpace(value)
+		} else if key != "" {
+			r.params[key] += "," + part
+		} else {
+			return nil, fmt.Errorf("Malformed rule - expected a parameter: %q", rulestring)
+		}
+	}
+
+	var err error
+	if r.kind == Lenia {
+		err = r.lenia()
+	} else {
+		err = r.smoothLife()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Malformed rule - %v: %q", err, rulestring)
+	}
+	return r, nil
+}
+
+// float parses a parameter as a number.
+func (r *Rule) float(key string) (float64, error) {
+	value, err := strconv.ParseFloat(r.params[key], 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("bad value for %s", key)
+	}
+	return value, nil
+}
+
+// floats parses several parameters as numbers, stopping at the first which can't be parsed.
+func (r *Rule) floats(keys ...string) ([]float64, error) {
+	values := make([]float64, len(keys))
+	for i, key := range keys {
+		var err error
+		if values[i], err = r.float(key); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// lenia builds a Lenia rule from its parameters.
+func (r *Rule) lenia() error {
+	values, err := r.floats("R", "T", "m", "s", "kn", "gn")
+	if err != nil {
+		return err
+	}
+	radius, steps, m, s := values[0], values[1], values[2], values[3]
+	core, growth := cores[int(values[4])], growths[int(values[5])]
+	if radius < 1 || radius > 100 || radius != math.Trunc(radius) || steps <= 0 || s <= 0 || core == nil || growth == nil {
+		return fmt.Errorf("parameter out of range")
+	}
+
+	var peaks []float64
+	for _, peak := range strings.Split(r.params["b"], ",") {
+		numerator, denominator, found := strings.Cut(strings.TrimSpace(peak), "/")
+		if !found {
+			denominator = "1"
+		}
+		n, err1 := strconv.ParseFloat(numerator, 64)
+		d, err2 := strconv.ParseFloat(denominator, 64)
+		if err1 != nil || err2 != nil || d == 0 || n < 0 {
+			return fmt.Errorf("bad value for b")
+		}
+		peaks = append(peaks, n/d)
+	}
+
+	r.kernels = []kernel{newKernel(int(radius), func(distance float64) float64 {
+		// Each ring takes up an equal share of the radius, with its own height.
+		d := distance / radius * float64(len(peaks))
+		if d >= float64(len(peaks)) {
+			return 0
+		}
+		ring := int(d)
+		return peaks[ring] * core(d-float64(ring))
+	})}
+	dt := 1 / steps
+	r.update = func(state float32, potentials []float32) float32 {
+		return clip(float64(state) + dt*growth(float64(potentials[0]), m, s))
+	}
+	return nil
+}
+
+// smoothLife builds a SmoothLife rule from its parameters.
+func (r *Rule) smoothLife() error {
+	values, err := r.floats("ra", "ri", "b1", "b2", "d1", "d2", "an", "am", "dt")
+	if err != nil {
+		return err
+	}
+	ra, ri, b1, b2, d1, d2, an, am, dt := values[0], values[1], values[2], values[3], values[4], values[5], values[6], values[7], values[8]
+	if ra < 2 || ra > 100 || ri <= 0 || ri >= ra || an <= 0 || am <= 0 || dt < 0 || dt > 1 {
+		return fmt.Errorf("parameter out of range")
+	}
+
+	// The edges of the disc and ring are smoothed over a cell's width, so that they look the same from any angle.
+	radius := int(math.Ceil(ra + 0.5))
+	inside := func(distance, edge float64) float64 {
+		return math.Max(0, math.Min(1, edge+0.5-distance))
+	}
+	r.kernels = []kernel{
+		newKernel(radius, func(distance float64) float64 { return inside(distance, ri) }),
+		newKernel(radius, func(distance float64) float64 { return inside(distance, ra) - inside(distance, ri) }),
+	}
+
+	sigmoid := func(x, a, alpha float64) float64 {
+		return 1 / (1 + math.Exp(-(x-a)*4/alpha))
+	}
+	r.update = func(state float32, potentials []float32) float32 {
+		m, n := float64(potentials[0]), float64(potentials[1])
+
+		// How alive the cell is picks out a point between the birth and death intervals, and the ring's fullness must
+		// fall within that.
+		alive := sigmoid(m, 0.5, am)
+		low := b1*(1-alive) + d1*alive
+		high := b2*(1-alive) + d2*alive
+		next := sigmoid(n, low, an) * (1 - sigmoid(n, high, an))
+		if dt == 0 {
+			return clip(next)
+		}
+		return clip(float64(state) + dt*(2*next-1))
+	}
+	return nil
+}
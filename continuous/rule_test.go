@@ -0,0 +1,110 @@
+package continuous
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/rle"
+)
+
+func TestParseRule(t *testing.T) {
+	Convey("Rules should be written out with every parameter, and read back the same", t, func() {
+		r, err := ParseRule("lenia:R=10,b=1,1/2,m=0.2")
+		So(err, ShouldBeNil)
+		So(r.String(), ShouldEqual, "Lenia:R=10,T=10,b=1,1/2,gn=1,kn=1,m=0.2,s=0.015")
+		again, err := ParseRule(r.String())
+		So(err, ShouldBeNil)
+		So(again.String(), ShouldEqual, r.String())
+	})
+
+	Convey("Kernels should add up to one", t, func() {
+		for _, rule := range []string{"Lenia:", "Lenia:b=1/4,1,2/3,kn=2", "SmoothLife:"} {
+			r, err := ParseRule(rule)
+			So(err, ShouldBeNil)
+			for _, k := range r.kernels {
+				var total float64
+				for _, w := range k.weights {
+					total += float64(w.w)
+				}
+				So(total, ShouldAlmostEqual, 1, 1e-4)
+			}
+		}
+	})
+
+	Convey("Malformed rules should return errors", t, func() {
+		for _, rule := range []string{"", "B3/S23", "Lenia:x=1", "Lenia:R=0", "Lenia:R=2.5", "Lenia:b=1/0", "Lenia:kn=5", "Lenia:1", "SmoothLife:ri=20", "SmoothLife:dt=2"} {
+			_, err := ParseRule(rule)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestLoadParameters(t *testing.T) {
+	Convey("Given a Lenia JSON file with several parameter sets", t, func() {
+		path := filepath.Join(t.TempDir(), "animals.json")
+		So(os.WriteFile(path, []byte(`[
+			{"code": "O2u", "name": "Orbium unicaudatus", "params": {"R": 13, "T": 10, "b": "1", "m": 0.15, "s": 0.015, "kn": 1, "gn": 1}},
+			{"code": "G3", "name": "Gyrorbium", "params": {"R": 18, "T": 10, "b": "1,1/3", "m": 0.156, "s": 0.0224, "kn": 1, "gn": 1}}
+		]`), 0644), ShouldBeNil)
+
+		Convey("The first should be used if no name is given", func() {
+			r, err := LoadParameters(path, "")
+			So(err, ShouldBeNil)
+			So(r.params["R"], ShouldEqual, "13")
+		})
+
+		Convey("Sets can be picked by name or by code", func() {
+			for _, name := range []string{"gyrorbium", "G3"} {
+				r, err := LoadParameters(path, name)
+				So(err, ShouldBeNil)
+				So(r.params["b"], ShouldEqual, "1,1/3")
+				So(r.params["s"], ShouldEqual, "0.0224")
+			}
+		})
+
+		Convey("A model should load them as its rule", func() {
+			m := New(40, 40)
+			So(m.SetRule(path+":O2u"), ShouldBeNil)
+			So(m.rule.params["m"], ShouldEqual, "0.15")
+			So(m.SetRule(path+":Nothing"), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestModel(t *testing.T) {
+	Convey("Every rule should keep the field between 0 and 1", t, func() {
+		for _, rule := range []string{"Lenia:R=6", "SmoothLife:ra=6,ri=2", "SmoothLife:ra=6,ri=2,dt=0.1"} {
+			m := New(24, 24)
+			So(m.SetRule(rule), ShouldBeNil)
+			m.Populate()
+			for i := 0; i < 5; i++ {
+				m.Next()
+			}
+			for _, v := range m.field {
+				So(v, ShouldBeBetweenOrEqual, 0, 1)
+			}
+		}
+	})
+
+	Convey("Exporting the field should keep each cell's value to within a 255th", t, func() {
+		m := New(20, 10)
+		m.Populate()
+		f, err := rle.Unmarshal(m.Export().Marshal())
+		So(err, ShouldBeNil)
+		n := New(20, 10)
+		n.Ingest(f)
+		So(n.rule.String(), ShouldEqual, m.rule.String())
+		for i := range m.field {
+			So(n.field[i], ShouldAlmostEqual, m.field[i], 0.5/255+1e-6)
+		}
+	})
+
+	Convey("Cells should be drawn in shades by their value", t, func() {
+		m := New(5, 1)
+		m.field = []float32{0, 0.25, 0.5, 0.75, 1}
+		So(m.String(), ShouldEqual, " ░▒▓█")
+	})
+}
@@ -0,0 +1,156 @@
+package elementary
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/makyo/gogol/rle"
+)
+
+// A one-dimensional automaton is a single row of cells, but the interesting part is how that row changes over time.
+// Every generation is kept, up to the height of the model, so that they can be drawn one beneath another as a
+// space-time diagram, with the oldest at the top and the current row at the bottom.
+
+type model struct {
+	width  int
+	height int
+	rows   [][]byte
+	rule   *Rule
+}
+
+// row returns the current row.
+func (m *model) row() []byte {
+	return m.rows[len(m.rows)-1]
+}
+
+// reset clears the history, leaving a single empty row.
+func (m *model) reset() {
+	m.rows = [][]byte{make([]byte, m.width)}
+}
+
+// Next evolves the row one generation, wrapping around the ends, and adds it to the history. Once the history is as
+// tall as the model, the oldest row scrolls off the top.
+func (m *model) Next() {
+	row := m.row()
+	next := make([]byte, m.width)
+	for x := range row {
+		index := 0
+		for dx := -m.rule.radius; dx <= m.rule.radius; dx++ {
+			cell := int(row[(x+dx+m.width)%m.width])
+			if m.rule.totalistic {
+				index += cell
+			} else {
+				index = index<<1 | cell
+			}
+		}
+		next[x] = m.rule.table[index]
+	}
+	m.rows = append(m.rows, next)
+	if len(m.rows) > m.height {
+		m.rows = append(m.rows[:0], m.rows[1:]...)
+	}
+}
+
+// Populate starts from a random row, where each cell has a 1 in 2 chance of being alive.
+func (m *model) Populate() {
+	m.reset()
+	for i := range m.row() {
+		m.row()[i] = byte(rand.Intn(2))
+	}
+}
+
+// PopulateSingle starts from a row with a single living cell in the middle.
+func (m *model) PopulateSingle() {
+	m.reset()
+	m.row()[m.width/2] = 1
+}
+
+// SetRule sets the rule the model follows from a rulestring such as W30 or T20R2.
+func (m *model) SetRule(rulestring string) error {
+	r, err := ParseRule(rulestring)
+	if err != nil {
+		return err
+	}
+	m.rule = r
+	return nil
+}
+
+// Ingest starts from the lowest row of the given field with any living cells. This means that a single row is taken
+// as it is, and an exported space-time diagram picks up from where it left off.
+func (m *model) Ingest(f *rle.RLEField) {
+	m.reset()
+	_, top, _, height := f.BoundingBox()
+	if height == 0 {
+		return
+	}
+	startX := (m.width - f.Width) / 2
+	for x, col := range f.Field[top+height-1] {
+		if col {
+			m.row()[((x+startX)%m.width+m.width)%m.width] = 1
+		}
+	}
+}
+
+// ToggleCell toggles whether the given cell of the current row is alive or dead. Earlier rows are history and can't
+// be changed, so the row clicked on doesn't matter.
+func (m *model) ToggleCell(x, y int) {
+	m.row()[x] ^= 1
+}
+
+// Export returns the current row as a field one cell tall.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, 1)
+	f.SetRule(m.rule.String())
+	for x, c := range m.row() {
+		f.Field[0][x] = c == 1
+	}
+	return f
+}
+
+// Diagram returns the space-time diagram as it is drawn, with any rows below the current one left blank.
+func (m *model) Diagram() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(m.rule.String())
+	for y, row := range m.rows {
+		for x, c := range row {
+			f.Field[y][x] = c == 1
+		}
+	}
+	return f
+}
+
+// String builds the space-time diagram to be printed by returning a • for a living cell or a space for a dead cell.
+// Until there are enough generations to fill the screen, the rows below the current one are left blank.
+func (m *model) String() string {
+	var frame strings.Builder
+	for y := 0; y < m.height; y++ {
+		if y > 0 {
+			frame.WriteString("\n")
+		}
+		if y >= len(m.rows) {
+			frame.WriteString(strings.Repeat(" ", m.width))
+			continue
+		}
+		for _, c := range m.rows[y] {
+			if c == 1 {
+				frame.WriteString("•")
+			} else {
+				frame.WriteString(" ")
+			}
+		}
+	}
+	return frame.String()
+}
+
+// New creates a model of the given size following rule 30, where the height is the number of generations shown at
+// once.
+func New(width, height int) *model {
+	r, _ := ParseRule("W30")
+	m := &model{
+		width:  width,
+		height: height,
+		rule:   r,
+	}
+	m.reset()
+	return m
+}
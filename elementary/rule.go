@@ -0,0 +1,69 @@
+package elementary
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rule is a one-dimensional rule with two states, where each cell's next state depends on itself and the cells within
+// some radius on either side of it.
+type Rule struct {
+	name   string
+	radius int
+
+	// totalistic is whether the next state depends only on the number of living cells in the neighborhood, rather
+	// than on which ones are alive.
+	totalistic bool
+
+	// table gives the next state for each neighborhood. For elementary rules, the neighborhood is the left, center,
+	// and right cells read as a binary number; for totalistic ones, it's the number of living cells.
+	table []byte
+}
+
+// String returns the rulestring the rule was parsed from.
+func (r *Rule) String() string {
+	return r.name
+}
+
+// ParseRule parses either an elementary rule by its Wolfram number, such as W30 or W110, or a totalistic rule by its
+// code and radius, such as T20R2. In a totalistic code, bit n gives the next state of a cell when n cells of its
+// neighborhood, including itself, are alive.
+func ParseRule(rulestring string) (*Rule, error) {
+	r := &Rule{name: strings.TrimSpace(rulestring), radius: 1}
+	malformed := fmt.Errorf("Malformed rule - must take the form 'W#' from 0 to 255 or 'T#R#' with a radius from 1 to 4: %q", rulestring)
+
+	spec := strings.ToUpper(r.name)
+	var code uint64
+	var err error
+	switch {
+	case strings.HasPrefix(spec, "W"):
+		code, err = strconv.ParseUint(spec[1:], 10, 8)
+		if err != nil {
+			return nil, malformed
+		}
+		r.table = make([]byte, 8)
+	case strings.HasPrefix(spec, "T"):
+		codeSpec, radiusSpec, found := strings.Cut(spec[1:], "R")
+		if !found {
+			return nil, malformed
+		}
+		r.radius, err = strconv.Atoi(radiusSpec)
+		if err != nil || r.radius < 1 || r.radius > 4 {
+			return nil, malformed
+		}
+		r.totalistic = true
+		r.table = make([]byte, 2*r.radius+2)
+		code, err = strconv.ParseUint(codeSpec, 10, len(r.table))
+		if err != nil {
+			return nil, malformed
+		}
+	default:
+		return nil, malformed
+	}
+
+	for i := range r.table {
+		r.table[i] = byte(code >> i & 1)
+	}
+	return r, nil
+}
@@ -0,0 +1,122 @@
+package elementary
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/rle"
+)
+
+// rows returns the living cells of each row kept by the model, as strings of o and . characters.
+func rows(m *model) []string {
+	var out []string
+	for _, row := range m.rows {
+		var line strings.Builder
+		for _, c := range row {
+			line.WriteString(map[byte]string{0: ".", 1: "o"}[c])
+		}
+		out = append(out, line.String())
+	}
+	return out
+}
+
+func TestParseRule(t *testing.T) {
+	Convey("Elementary rules should be read as binary numbers", t, func() {
+		r, err := ParseRule("W110")
+		So(err, ShouldBeNil)
+		So(r.table, ShouldResemble, []byte{0, 1, 1, 1, 0, 1, 1, 0})
+	})
+
+	Convey("Totalistic rules should have a state for each count", t, func() {
+		r, err := ParseRule("T20R2")
+		So(err, ShouldBeNil)
+		So(r.radius, ShouldEqual, 2)
+		So(r.table, ShouldResemble, []byte{0, 0, 1, 0, 1, 0})
+	})
+
+	Convey("Malformed rules should return errors", t, func() {
+		for _, rule := range []string{"", "30", "W256", "W-1", "T20", "T20R0", "T20R5", "T64R2", "B3/S23"} {
+			_, err := ParseRule(rule)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestNext(t *testing.T) {
+	Convey("Rule 30 should grow its familiar triangle from a single cell", t, func() {
+		m := New(9, 9)
+		m.PopulateSingle()
+		for i := 0; i < 3; i++ {
+			m.Next()
+		}
+		So(rows(m), ShouldResemble, []string{
+			"....o....",
+			"...ooo...",
+			"..oo..o..",
+			".oo.oooo.",
+		})
+	})
+
+	Convey("Rule 150 should match the totalistic rule which is alive for odd counts", t, func() {
+		a, b := New(32, 10), New(32, 10)
+		b.SetRule("T10R1")
+		a.SetRule("W150")
+		a.Populate()
+		b.Ingest(a.Export())
+		for i := 0; i < 20; i++ {
+			a.Next()
+			b.Next()
+		}
+		So(rows(b), ShouldResemble, rows(a))
+	})
+
+	Convey("Once the screen is full, the oldest generations should scroll off the top", t, func() {
+		m := New(16, 4)
+		rand.Seed(1)
+		m.Populate()
+		var history []string
+		for i := 0; i < 10; i++ {
+			history = append(history, rows(m)[len(m.rows)-1])
+			m.Next()
+		}
+		history = append(history, rows(m)[len(m.rows)-1])
+		So(rows(m), ShouldResemble, history[7:])
+	})
+
+	Convey("A single row pattern should be taken as the first generation, along with its rule", t, func() {
+		f, err := rle.Unmarshal(`x = 3, y = 1, rule = W90
+obo!`)
+		So(err, ShouldBeNil)
+		m := New(7, 3)
+		So(m.SetRule(f.Rule), ShouldBeNil)
+		m.Ingest(f)
+		So(m.rule.String(), ShouldEqual, "W90")
+		So(rows(m), ShouldResemble, []string{"..o.o.."})
+
+		m.Next()
+		m.Next()
+		So(m.Diagram().Field[2], ShouldResemble, []bool{true, false, true, false, true, false, true})
+
+		m.Ingest(m.Diagram())
+		So(rows(m), ShouldResemble, []string{"o.o.o.o"})
+	})
+
+	Convey("Exporting should give only the current row, while the diagram keeps the history", t, func() {
+		m := New(9, 5)
+		m.PopulateSingle()
+		m.Next()
+		f := m.Export()
+		So(f.Width, ShouldEqual, 9)
+		So(f.Height, ShouldEqual, 1)
+		So(f.Rule, ShouldEqual, "W30")
+		So(f.Field[0], ShouldResemble, []bool{false, false, false, true, true, true, false, false, false})
+
+		d := m.Diagram()
+		So(d.Height, ShouldEqual, 5)
+		So(d.Field[0][4], ShouldBeTrue)
+		So(d.Field[1], ShouldResemble, f.Field[0])
+	})
+}
@@ -0,0 +1,184 @@
+package generations
+
+import (
+	"image/color"
+	"math/rand"
+
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/render"
+	"github.com/makyo/gogol/rle"
+)
+
+// Each cell is a single byte holding its state: 0 for dead, 1 for alive, and anything higher for the refractory
+// states a cell passes through as it dies. Only living cells count as neighbors.
+//
+// Every cell is worked out anew each generation, so rules with B0 need nothing special: the background really does
+// come alive, then passes through the refractory states with everything else. Strobing it the way the isotropic engine
+// does wouldn't work here, as a dying background can't be stored as off.
+
+const (
+	dead  = 0
+	alive = 1
+)
+
+type model struct {
+	width  int
+	height int
+	field  []byte
+	rule   *Rule
+}
+
+// neighbors counts the living cells around the given cell, wrapping around the edges.
+func (m *model) neighbors(x, y int) int {
+	count := 0
+	for _, pos := range base.Neighbors(x, y, m.width, m.height) {
+		if m.field[pos] == alive {
+			count++
+		}
+	}
+	return count
+}
+
+// nextState returns the next state of the given cell. Dead cells are born and living cells survive according to the
+// rule as usual, but living cells which don't survive, and cells which are already dying, move on to the next state
+// instead of dying outright.
+func (m *model) nextState(x, y int) byte {
+	switch state := m.field[y*m.width+x]; state {
+	case dead:
+		if m.rule.born[m.neighbors(x, y)] {
+			return alive
+		}
+		return dead
+	case alive:
+		if m.rule.survive[m.neighbors(x, y)] {
+			return alive
+		}
+		return byte(2 % m.rule.states)
+	default:
+		return byte((int(state) + 1) % m.rule.states)
+	}
+}
+
+// Next evolves the field one generation.
+func (m *model) Next() {
+	next := make([]byte, len(m.field))
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			next[y*m.width+x] = m.nextState(x, y)
+		}
+	}
+	m.field = next
+}
+
+// Size returns the width and height of the field.
+func (m *model) Size() (int, int) {
+	return m.width, m.height
+}
+
+// State returns the state of the given cell.
+func (m *model) State(x, y int) int {
+	return int(m.field[y*m.width+x])
+}
+
+// SetState sets the state of the given cell.
+func (m *model) SetState(x, y, state int) {
+	m.field[y*m.width+x] = byte(state)
+}
+
+// NextState returns the state the given cell would have after the next generation, given its neighbors as they stand.
+func (m *model) NextState(x, y int) int {
+	return int(m.nextState(x, y))
+}
+
+// Populate generates a random field of automata, where each cell has a 1 in 5 chance of being alive.
+func (m *model) Populate() {
+	for i, _ := range m.field {
+		m.field[i] = dead
+		if rand.Intn(5) == 0 {
+			m.field[i] = alive
+		}
+	}
+}
+
+// SetRule sets the rule the model follows from a rulestring such as B2/S/C3 or 345/2/4. Any cells in states which
+// the new rule doesn't have are killed.
+func (m *model) SetRule(rulestring string) error {
+	r, err := ParseRule(rulestring)
+	if err != nil {
+		return err
+	}
+	m.rule = r
+	for i, c := range m.field {
+		if int(c) >= r.states {
+			m.field[i] = dead
+		}
+	}
+	return nil
+}
+
+// Ingest sets the field to the given value. Cells in states past the rule's last dying state are left dead.
+func (m *model) Ingest(f *rle.RLEField) {
+	startX := (m.width - f.Width) / 2
+	startY := (m.height - f.Height) / 2
+	for y, row := range f.Field {
+		for x, _ := range row {
+			state := f.State(x, y)
+			if state == dead || state >= m.rule.states {
+				continue
+			}
+			m.field[((y+startY+m.height)%m.height)*m.width+(x+startX+m.width)%m.width] = byte(state)
+		}
+	}
+}
+
+// ToggleCell toggles whether the given cell is alive or dead.
+func (m *model) ToggleCell(x, y int) {
+	pos := y*m.width + x
+	if m.field[pos] == dead {
+		m.field[pos] = alive
+	} else {
+		m.field[pos] = dead
+	}
+}
+
+// Export returns the current state of the field, including the refractory states.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(m.rule.String())
+	for i, c := range m.field {
+		f.SetState(i%m.width, i/m.width, int(c))
+	}
+	return f
+}
+
+// Colors used for drawing cells: living cells are white, while dying cells fade from yellow to dark red.
+var (
+	aliveColor  = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	dyingColor  = color.RGBA{0xff, 0xd0, 0x00, 0xff}
+	fadingColor = color.RGBA{0x50, 0x00, 0x00, 0xff}
+)
+
+// stateColor returns the color used to draw the given state.
+func (m *model) stateColor(state byte) color.Color {
+	if state == alive {
+		return aliveColor
+	}
+	return render.Decay(dyingColor, fadingColor, state, m.rule.states)
+}
+
+// String builds the entire screen's worth of cells to be printed by returning a • for each living or dying cell, in a
+// color which shows how far along it is in dying, or a space for a dead cell.
+func (m *model) String() string {
+	return render.ColorRuns(m.field, m.width, m.stateColor)
+}
+
+// New creates a model of the given size following the rules of Brian's Brain (B2/S/C3).
+func New(width, height int) *model {
+	r, _ := ParseRule("B2/S/C3")
+	return &model{
+		width:  width,
+		height: height,
+		field:  make([]byte, width*height),
+		rule:   r,
+	}
+}
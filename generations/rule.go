@@ -0,0 +1,86 @@
+package generations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rule is a Generations rule: a birth/survival rule where cells which die pass through a number of refractory states
+// before becoming dead (see: https://conwaylife.com/wiki/Generations ).
+type Rule struct {
+	name          string
+	born, survive [9]bool
+
+	// states is the total number of states, including dead and alive.
+	states int
+}
+
+// String returns the rulestring the rule was parsed from.
+func (r *Rule) String() string {
+	return r.name
+}
+
+// States returns the total number of states cells may be in, including dead and alive.
+func (r *Rule) States() int {
+	return r.states
+}
+
+// ParseRule parses a Generations rulestring, either in the form B2/S/C3 or in the older form S/B/C, such as 345/2/4.
+// Plain birth/survival rules such as B3/S23 are treated as having two states. Rules may include B0.
+func ParseRule(rulestring string) (*Rule, error) {
+	r := &Rule{name: strings.TrimSpace(rulestring), states: 2}
+	malformed := fmt.Errorf("Malformed rule - must take the form 'B#/S#/C#' or 'S/B/C': %q", rulestring)
+
+	parts := strings.Split(r.name, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, malformed
+	}
+
+	var born, survive, states string
+	if strings.IndexAny(strings.ToUpper(r.name), "BSCG") < 0 {
+		// The older S/B/C form has no letters.
+		if len(parts) != 3 {
+			return nil, malformed
+		}
+		survive, born, states = parts[0], parts[1], parts[2]
+	} else {
+		for _, part := range parts {
+			if part == "" {
+				return nil, malformed
+			}
+			switch strings.ToUpper(part[:1]) {
+			case "B":
+				born = part[1:]
+			case "S":
+				survive = part[1:]
+			case "C", "G":
+				states = part[1:]
+			default:
+				// Golly also allows the number of states without a letter.
+				states = part
+			}
+		}
+	}
+
+	for _, c := range born {
+		if c < '0' || c > '8' {
+			return nil, malformed
+		}
+		r.born[c-'0'] = true
+	}
+	for _, c := range survive {
+		if c < '0' || c > '8' {
+			return nil, malformed
+		}
+		r.survive[c-'0'] = true
+	}
+	if states != "" {
+		count, err := strconv.Atoi(states)
+		if err != nil || count < 2 || count > 256 {
+			return nil, malformed
+		}
+		r.states = count
+	}
+	return r, nil
+}
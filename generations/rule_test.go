@@ -0,0 +1,126 @@
+package generations
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/rle"
+)
+
+func TestParseRule(t *testing.T) {
+	Convey("Rules can be parsed in either notation", t, func() {
+		for _, rulestring := range []string{"345/2/4", "B2/S345/C4", "B2/S345/4", "S345/B2/C4"} {
+			r, err := ParseRule(rulestring)
+			So(err, ShouldBeNil)
+			So(r.born, ShouldEqual, [9]bool{false, false, true})
+			So(r.survive, ShouldEqual, [9]bool{false, false, false, true, true, true})
+			So(r.States(), ShouldEqual, 4)
+		}
+	})
+
+	Convey("Brian's Brain has no survival conditions", t, func() {
+		r, err := ParseRule("/2/3")
+		So(err, ShouldBeNil)
+		So(r.survive, ShouldEqual, [9]bool{})
+		So(r.States(), ShouldEqual, 3)
+	})
+
+	Convey("Rules may include B0", t, func() {
+		r, err := ParseRule("B0/S/C3")
+		So(err, ShouldBeNil)
+		So(r.born[0], ShouldBeTrue)
+	})
+
+	Convey("Malformed rules should return errors", t, func() {
+		for _, rulestring := range []string{"", "2/3", "345/2/1", "345/2/x", "B9/S/C3"} {
+			_, err := ParseRule(rulestring)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestNext(t *testing.T) {
+	Convey("Given a Brian's Brain pattern", t, func() {
+		// A block of living cells with a row of dying cells above and below it.
+		f, err := rle.Unmarshal(`x = 2, y = 4, rule = /2/3
+2B$2A$2A$2B!`)
+		So(err, ShouldBeNil)
+		m := New(16, 16)
+		So(m.SetRule(f.Rule), ShouldBeNil)
+		m.Ingest(f)
+
+		Convey("Living cells should pass through the dying state before dying", func() {
+			m.Next()
+			e := m.Export()
+			states := 0
+			for y, row := range e.Field {
+				for x, _ := range row {
+					if e.State(x, y) == 2 {
+						states++
+					}
+				}
+			}
+			// Only the four cells which were alive should now be dying; the cells that were dying are now dead.
+			So(states, ShouldEqual, 4)
+		})
+
+		Convey("The exported field should be a multi-state field that can be read back", func() {
+			g, err := rle.Unmarshal(m.Export().Marshal())
+			So(err, ShouldBeNil)
+			So(g.Rule, ShouldEqual, "/2/3")
+			So(g.States, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestBirthsWithNoNeighbors(t *testing.T) {
+	Convey("Given an empty field following a rule with B0", t, func() {
+		m := New(8, 8)
+		So(m.SetRule("B0/S/C3"), ShouldBeNil)
+
+		Convey("The whole field should come alive, then pass through the dying state, then die", func() {
+			for _, state := range []int{alive, 2, dead, alive} {
+				m.Next()
+				for _, c := range m.field {
+					So(c, ShouldEqual, state)
+				}
+			}
+		})
+	})
+
+	Convey("Given a glider following a rule with B0", t, func() {
+		f, err := rle.Unmarshal(`x = 3, y = 3, rule = B013/S2/C4
+bo$2bo$3o!`)
+		So(err, ShouldBeNil)
+		m := New(16, 16)
+		So(m.SetRule(f.Rule), ShouldBeNil)
+		m.Ingest(f)
+
+		Convey("It should match the rule applied directly to every cell", func() {
+			for i := 0; i < 6; i++ {
+				expected := make([]byte, len(m.field))
+				for y := 0; y < m.height; y++ {
+					for x := 0; x < m.width; x++ {
+						count := 0
+						for dy := -1; dy <= 1; dy++ {
+							for dx := -1; dx <= 1; dx++ {
+								if (dx != 0 || dy != 0) && m.field[(y+dy+m.height)%m.height*m.width+(x+dx+m.width)%m.width] == alive {
+									count++
+								}
+							}
+						}
+						switch state := m.field[y*m.width+x]; {
+						case state == dead && m.rule.born[count], state == alive && m.rule.survive[count]:
+							expected[y*m.width+x] = alive
+						case state != dead:
+							expected[y*m.width+x] = byte((int(state) + 1) % m.rule.states)
+						}
+					}
+				}
+				m.Next()
+				So(m.field, ShouldResemble, expected)
+			}
+		})
+	})
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+
+	"github.com/makyo/gogol/render"
+)
+
+// gifCommand records a run of a model to an animated GIF without starting the UI.
+//
+//	gogol gif [flags] [pattern.rle]
+//
+// If no pattern is given, the field is populated at random.
+func gifCommand(args []string) error {
+	fs := flag.NewFlagSet("gif", flag.ExitOnError)
+	start := modelFlags(fs, 128)
+	out := fs.String("o", "out.gif", "File to write the animation to")
+	generations := fs.Int("generations", 100, "Number of generations to run")
+	every := fs.Int("every", 1, "Record only every nth generation")
+	delay := fs.Int("delay", 10, "Delay between frames in hundredths of a second")
+	cellSize := fs.Int("cell", 4, "Size of each cell in pixels")
+	grid := fs.Bool("grid", false, "Draw grid lines between cells")
+	bounds := fs.Bool("bounds", false, "Crop to the bounding box of the pattern over the whole run")
+	region := fs.String("region", "", "Crop to a region of the field given as x,y,width,height")
+	recent := fs.Bool("recent", false, "Draw cells which have just died in a different color")
+	fs.Parse(args)
+
+	m, err := start()
+	if err != nil {
+		return err
+	}
+
+	opts := render.AnimationOptions{
+		Options:      render.DefaultOptions(),
+		Generations:  *generations,
+		Every:        *every,
+		Delay:        *delay,
+		FollowBounds: *bounds,
+	}
+	opts.CellSize = *cellSize
+	opts.Grid = *grid
+	if *recent {
+		opts.RecentlyDead = color.RGBA{0x80, 0x20, 0x20, 0xff}
+	}
+	if *region != "" {
+		var x, y, w, h int
+		if _, err := fmt.Sscanf(*region, "%d,%d,%d,%d", &x, &y, &w, &h); err != nil {
+			return fmt.Errorf("Malformed region - must take the form 'x,y,width,height': %q", *region)
+		}
+		opts.Region = image.Rect(x, y, x+w, y+h)
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := render.EncodeAnimation(file, m, opts); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,178 @@
+package hex
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/makyo/gogol/rle"
+)
+
+// Like Golly, this emulates a hexagonal grid on a square one by leaving out the northeast and southwest neighbors, so
+// that each cell's neighbors are:
+//
+//	NW N  .
+//	W  .  E
+//	.  S  SE
+//
+// On screen, each row is drawn half a cell to the left of the one above it, which turns that back into a hexagon.
+//
+// Under rules with B0, the field may be stored complemented, so that the background is always off. Cells are then
+// alive when their value differs from the background.
+
+type model struct {
+	width      int
+	height     int
+	field      []byte
+	rule       *Rule
+	background byte
+}
+
+// neighbors counts the living cells around the given cell, wrapping around the edges.
+func (m *model) neighbors(x, y int) int {
+	up := ((y + m.height - 1) % m.height) * m.width
+	row := y * m.width
+	down := ((y + 1) % m.height) * m.width
+	left := (x + m.width - 1) % m.width
+	right := (x + 1) % m.width
+	return int(m.field[up+left] + m.field[up+x] + m.field[row+left] + m.field[row+right] + m.field[down+x] + m.field[down+right])
+}
+
+// Next evolves the field one generation. When the background is on, both the cell and its neighbors are complemented
+// before applying the rule, and the result is stored relative to whatever the background becomes.
+func (m *model) Next() {
+	next := make([]byte, len(m.field))
+	background := m.rule.next(m.background, 6*int(m.background))
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			pos := y*m.width + x
+			count := m.neighbors(x, y)
+			if m.background == 1 {
+				count = 6 - count
+			}
+			next[pos] = m.rule.next(m.field[pos]^m.background, count) ^ background
+		}
+	}
+	m.field = next
+	m.background = background
+}
+
+// Size returns the width and height of the field.
+func (m *model) Size() (int, int) {
+	return m.width, m.height
+}
+
+// State returns 1 if the given cell is alive and 0 if it is dead.
+func (m *model) State(x, y int) int {
+	return int(m.field[y*m.width+x] ^ m.background)
+}
+
+// SetState sets the given cell to alive (1) or dead (0).
+func (m *model) SetState(x, y, state int) {
+	m.field[y*m.width+x] = byte(state) ^ m.background
+}
+
+// NextState returns the state the given cell would have after the next generation, given its neighbors as they stand.
+func (m *model) NextState(x, y int) int {
+	count := m.neighbors(x, y)
+	if m.background == 1 {
+		count = 6 - count
+	}
+	return int(m.rule.next(byte(m.State(x, y)), count))
+}
+
+// Populate generates a random field of automata, where each cell has a 1 in 5 chance of being alive.
+func (m *model) Populate() {
+	m.background = 0
+	for i, _ := range m.field {
+		m.field[i] = 0
+		if rand.Intn(5) == 0 {
+			m.field[i] = 1
+		}
+	}
+}
+
+// SetRule sets the rule the model follows from a rulestring such as B2/S34H.
+func (m *model) SetRule(rulestring string) error {
+	r, err := ParseRule(rulestring)
+	if err != nil {
+		return err
+	}
+	m.rule = r
+	return nil
+}
+
+// Ingest sets the field to the given value. Patterns are expected to be in the same sheared layout that Golly uses.
+func (m *model) Ingest(f *rle.RLEField) {
+	startX := (m.width - f.Width) / 2
+	startY := (m.height - f.Height) / 2
+	for y, row := range f.Field {
+		for x, col := range row {
+			if col {
+				m.field[((y+startY+m.height)%m.height)*m.width+(x+startX+m.width)%m.width] = 1 ^ m.background
+			}
+		}
+	}
+}
+
+// RowShift returns how many columns of the screen the given row is moved to the right. Each row moves one column (half
+// a cell) left of the one above it, wrapping around since the field is a torus anyway.
+func (m *model) RowShift(y int) int {
+	return m.height - 1 - y
+}
+
+// ToggleCell toggles whether the cell drawn at the given position on screen is alive or dead.
+func (m *model) ToggleCell(x, y int) {
+	span := 2 * m.width
+	pos := y*m.width + ((x-m.RowShift(y))%span+span)%span/2
+	m.field[pos] ^= 1
+}
+
+// CellWidth returns the number of columns used to draw each cell.
+func (m *model) CellWidth() int {
+	return 2
+}
+
+// Export returns the current state of the field in Golly's sheared layout.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(m.rule.String())
+	for i, c := range m.field {
+		f.Field[i/m.width][i%m.width] = c != m.background
+	}
+	return f
+}
+
+// String builds the entire screen's worth of cells to be printed by returning a • for a living cell or a space for a
+// dead cell. Each cell takes two columns, and each row is offset by one column from the last so that the cells form a
+// hexagonal grid.
+func (m *model) String() string {
+	var frame strings.Builder
+	span := 2 * m.width
+	line := make([]rune, span)
+	for y := 0; y < m.height; y++ {
+		if y > 0 {
+			frame.WriteString("\n")
+		}
+		for i := range line {
+			line[i] = ' '
+		}
+		for x := 0; x < m.width; x++ {
+			if m.field[y*m.width+x] != m.background {
+				line[(2*x+m.RowShift(y))%span] = '•'
+			}
+		}
+		frame.WriteString(string(line))
+	}
+	return frame.String()
+}
+
+// New creates a model of the given size, in cells, following the B2/S34H rule.
+func New(width, height int) *model {
+	r, _ := ParseRule("B2/S34H")
+	return &model{
+		width:  width,
+		height: height,
+		field:  make([]byte, width*height),
+		rule:   r,
+	}
+}
@@ -0,0 +1,54 @@
+package hex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule is an outer totalistic rule on a hexagonal grid, where each cell has six neighbors.
+type Rule struct {
+	name          string
+	born, survive [7]bool
+}
+
+// String returns the rulestring the rule was parsed from.
+func (r *Rule) String() string {
+	return r.name
+}
+
+// next returns the next state of a cell, given its state and number of living neighbors.
+func (r *Rule) next(state byte, count int) byte {
+	if state == 0 && r.born[count] || state == 1 && r.survive[count] {
+		return 1
+	}
+	return 0
+}
+
+// ParseRule parses a hexagonal rule in birth/survival notation followed by an H, such as B2/S34H. Rules may include
+// B0.
+func ParseRule(rulestring string) (*Rule, error) {
+	r := &Rule{name: strings.TrimSpace(rulestring)}
+	malformed := fmt.Errorf("Malformed rule - must take the form 'B#/S#H' with counts from 0 to 6: %q", rulestring)
+
+	spec := strings.ToUpper(r.name)
+	if !strings.HasSuffix(spec, "H") {
+		return nil, malformed
+	}
+	born, survive, found := strings.Cut(strings.TrimSuffix(spec, "H"), "/")
+	if !found || !strings.HasPrefix(born, "B") || !strings.HasPrefix(survive, "S") {
+		return nil, malformed
+	}
+	for _, c := range born[1:] {
+		if c < '0' || c > '6' {
+			return nil, malformed
+		}
+		r.born[c-'0'] = true
+	}
+	for _, c := range survive[1:] {
+		if c < '0' || c > '6' {
+			return nil, malformed
+		}
+		r.survive[c-'0'] = true
+	}
+	return r, nil
+}
@@ -0,0 +1,93 @@
+package hex
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseRule(t *testing.T) {
+	Convey("When parsing a hexagonal rule", t, func() {
+		r, err := ParseRule("B2/S34H")
+		So(err, ShouldBeNil)
+		So(r.born, ShouldEqual, [7]bool{false, false, true})
+		So(r.survive, ShouldEqual, [7]bool{false, false, false, true, true})
+	})
+
+	Convey("Malformed rules should return errors", t, func() {
+		for _, rule := range []string{"", "B2/S34", "B2/S37H", "B7/S34H", "S34/B2H"} {
+			_, err := ParseRule(rule)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestNext(t *testing.T) {
+	Convey("Given a single cell following a rule where any cell with one neighbor is born", t, func() {
+		m := New(5, 5)
+		m.SetRule("B1/SH")
+		m.field[2*5+2] = 1
+		m.Next()
+
+		Convey("Only the six hexagonal neighbors should be born", func() {
+			So(m.Export().Field, ShouldResemble, [][]bool{
+				{false, false, false, false, false},
+				{false, true, true, false, false},
+				{false, true, false, true, false},
+				{false, false, true, true, false},
+				{false, false, false, false, false},
+			})
+		})
+	})
+
+	Convey("Toggling a cell on screen should toggle the cell drawn there", t, func() {
+		m := New(5, 5)
+		for y := 0; y < 5; y++ {
+			for x := 0; x < 10; x += 2 {
+				m.ToggleCell(x+m.RowShift(y), y)
+			}
+		}
+		for _, c := range m.field {
+			So(c, ShouldEqual, 1)
+		}
+	})
+}
+
+func TestStrobing(t *testing.T) {
+	Convey("Given a single cell following a rule with B0", t, func() {
+		m := New(16, 16)
+		m.SetRule("B01/S2H")
+		m.ToggleCell(16+m.RowShift(8), 8)
+
+		Convey("The whole background should flash on and off, while being stored as off", func() {
+			m.Next()
+			f := m.Export()
+			So(f.Field[0][0], ShouldBeTrue)
+			So(f.Field[8][8], ShouldBeFalse)
+			So(m.field[0], ShouldEqual, 0)
+
+			m.Next()
+			f = m.Export()
+			So(f.Field[0][0], ShouldBeFalse)
+			So(m.field[0], ShouldEqual, 0)
+		})
+
+		Convey("It should match the rule applied to the cells as they are shown", func() {
+			for i := 0; i < 6; i++ {
+				f := m.Export()
+				m.Next()
+				for y, row := range m.Export().Field {
+					for x, col := range row {
+						count := 0
+						for _, d := range [6][2]int{{-1, -1}, {0, -1}, {-1, 0}, {1, 0}, {0, 1}, {1, 1}} {
+							if f.Field[(y+d[1]+16)%16][(x+d[0]+16)%16] {
+								count++
+							}
+						}
+						So(col, ShouldEqual, !f.Field[y][x] && m.rule.born[count] || f.Field[y][x] && m.rule.survive[count])
+					}
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,155 @@
+package isotropic
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/makyo/gogol/rle"
+)
+
+// Rather than keeping a count of neighbors as the other engines do, this one looks at the whole 3x3 neighborhood of
+// every cell, which lets it follow rules that care about where the neighbors are and not just how many there are.
+//
+// Under rules with B0, the field may be stored complemented, so that the background is always off. Cells are then
+// alive when their value differs from the background.
+
+type model struct {
+	width      int
+	height     int
+	field      []byte
+	rule       *Rule
+	background byte
+}
+
+// column returns the cells in column x of the rows starting at up, row, and down, packed into the west column of a
+// neighborhood. Shifting left by one or two moves them into the middle or east columns.
+func (m *model) column(up, row, down, x int) int {
+	return int(m.field[up+x]) | int(m.field[row+x])<<3 | int(m.field[down+x])<<6
+}
+
+// Next evolves the field one generation by looking up each cell's neighborhood in the rule.
+func (m *model) Next() {
+	next := make([]byte, len(m.field))
+	step := &m.rule.steps[m.background]
+	for y := 0; y < m.height; y++ {
+		up := ((y + m.height - 1) % m.height) * m.width
+		row := y * m.width
+		down := ((y + 1) % m.height) * m.width
+
+		// Slide the neighborhood along the row, dropping the west column and adding a new east column at each step.
+		hood := m.column(up, row, down, m.width-1) | m.column(up, row, down, 0)<<1
+		for x := 0; x < m.width; x++ {
+			hood |= m.column(up, row, down, (x+1)%m.width) << 2
+			next[row+x] = step[hood]
+			hood = (hood >> 1) & (nw | n | w | center | sw | s)
+		}
+	}
+	m.field = next
+	m.background = m.rule.backgrounds[m.background]
+}
+
+// Size returns the width and height of the field.
+func (m *model) Size() (int, int) {
+	return m.width, m.height
+}
+
+// State returns 1 if the given cell is alive and 0 if it is dead.
+func (m *model) State(x, y int) int {
+	return int(m.field[y*m.width+x] ^ m.background)
+}
+
+// SetState sets the given cell to alive (1) or dead (0).
+func (m *model) SetState(x, y, state int) {
+	m.field[y*m.width+x] = byte(state) ^ m.background
+}
+
+// NextState returns the state the given cell would have after the next generation, by looking up its neighborhood in
+// the rule as it stands.
+func (m *model) NextState(x, y int) int {
+	hood := 0
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if m.State((x+dx+m.width)%m.width, (y+dy+m.height)%m.height) == 1 {
+				hood |= 1 << ((dy+1)*3 + dx + 1)
+			}
+		}
+	}
+	return int(m.rule.table[hood])
+}
+
+// Populate generates a random field of automata, where each cell has a 1 in 5 chance of being alive.
+func (m *model) Populate() {
+	m.background = 0
+	for i, _ := range m.field {
+		m.field[i] = 0
+		if rand.Intn(5) == 0 {
+			m.field[i] = 1
+		}
+	}
+}
+
+// SetRule sets the rule the model follows from a rulestring such as B2n3/S23-q.
+func (m *model) SetRule(rulestring string) error {
+	r, err := ParseRule(rulestring)
+	if err != nil {
+		return err
+	}
+	m.rule = r
+	return nil
+}
+
+// Ingest sets the field to the given value.
+func (m *model) Ingest(f *rle.RLEField) {
+	startX := (m.width - f.Width) / 2
+	startY := (m.height - f.Height) / 2
+	for y, row := range f.Field {
+		for x, col := range row {
+			if col {
+				m.field[((y+startY+m.height)%m.height)*m.width+(x+startX+m.width)%m.width] = 1 ^ m.background
+			}
+		}
+	}
+}
+
+// ToggleCell toggles whether the given cell is alive or dead.
+func (m *model) ToggleCell(x, y int) {
+	pos := y*m.width + x
+	m.field[pos] ^= 1
+}
+
+// Export returns the current state of the field.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(m.rule.String())
+	for i, c := range m.field {
+		f.Field[i/m.width][i%m.width] = c != m.background
+	}
+	return f
+}
+
+// String builds the entire screen's worth of cells to be printed by returning a • for a living cell or a space for a dead cell.
+func (m *model) String() string {
+	var frame strings.Builder
+	for i, c := range m.field {
+		if i > 0 && i%m.width == 0 {
+			frame.WriteString("\n")
+		}
+		if c != m.background {
+			frame.WriteString("•")
+		} else {
+			frame.WriteString(" ")
+		}
+	}
+	return frame.String()
+}
+
+// New creates a model of the given size following the rules of Conway's Game of Life.
+func New(width, height int) *model {
+	r, _ := ParseRule("B3/S23")
+	return &model{
+		width:  width,
+		height: height,
+		field:  make([]byte, width*height),
+		rule:   r,
+	}
+}
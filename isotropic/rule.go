@@ -0,0 +1,236 @@
+package isotropic
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// A neighborhood is the state of a cell and its eight neighbors packed into nine bits, row by row from the northwest
+// corner. This is the same layout Golly uses, so the neighborhoods below can be checked against it.
+const (
+	nw = 1 << iota
+	n
+	ne
+	w
+	center
+	e
+	sw
+	s
+	se
+
+	// All eight neighbors, without the center cell.
+	neighbors = 0x1ff &^ center
+
+	// Just the four orthogonal neighbors used by von Neumann rules.
+	orthogonal = n | w | e | s
+)
+
+// In Hensel notation, each letter following a neighbor count picks out the neighborhoods with that count which match a
+// particular shape, up to rotation and reflection (see: https://conwaylife.com/wiki/Isotropic_non-totalistic_rule ).
+// These are the letters valid for counts of zero through four neighbors.
+var henselLetters = [5]string{"", "ce", "ceaikn", "ceaiknjqry", "ceaiknjqrytwz"}
+
+// These are a representative neighborhood for each of the letters above. Counts of five through eight use the same
+// letters, standing for the complements of the neighborhoods with eight minus that count.
+var henselNeighborhoods = [5][]int{
+	{},
+	{1, 2},
+	{5, 10, 3, 40, 33, 68},
+	{69, 42, 11, 7, 98, 13, 14, 70, 41, 97},
+	{325, 170, 15, 45, 99, 71, 106, 102, 43, 101, 105, 78, 108},
+}
+
+// letters maps every neighborhood (without its center cell) to its Hensel letter, or 0 for counts of zero and eight.
+var letters [512]byte
+
+func init() {
+	for count := 1; count < 8; count++ {
+		for i, rep := range representatives(count) {
+			for _, sym := range symmetries(rep) {
+				letters[sym] = lettersFor(count)[i]
+			}
+		}
+	}
+}
+
+// lettersFor returns the Hensel letters valid for a given neighbor count.
+func lettersFor(count int) string {
+	if count > 4 {
+		return henselLetters[8-count]
+	}
+	return henselLetters[count]
+}
+
+// representatives returns a neighborhood of each shape for a given neighbor count, in the order of their letters.
+func representatives(count int) []int {
+	if count <= 4 {
+		return henselNeighborhoods[count]
+	}
+	reps := make([]int, len(henselNeighborhoods[8-count]))
+	for i, rep := range henselNeighborhoods[8-count] {
+		reps[i] = rep ^ neighbors
+	}
+	return reps
+}
+
+// transform moves each bit of a neighborhood to a new position, given as a function on x and y offsets from the center.
+func transform(hood int, f func(x, y int) (int, int)) int {
+	result := 0
+	for bit := 0; bit < 9; bit++ {
+		if hood&(1<<bit) == 0 {
+			continue
+		}
+		x, y := f(bit%3-1, bit/3-1)
+		result |= 1 << ((y+1)*3 + x + 1)
+	}
+	return result
+}
+
+// symmetries returns the eight rotations and reflections of a neighborhood.
+func symmetries(hood int) []int {
+	return []int{
+		hood,
+		transform(hood, func(x, y int) (int, int) { return -y, x }),
+		transform(hood, func(x, y int) (int, int) { return -x, -y }),
+		transform(hood, func(x, y int) (int, int) { return y, -x }),
+		transform(hood, func(x, y int) (int, int) { return -x, y }),
+		transform(hood, func(x, y int) (int, int) { return x, -y }),
+		transform(hood, func(x, y int) (int, int) { return y, x }),
+		transform(hood, func(x, y int) (int, int) { return -y, -x }),
+	}
+}
+
+// Rule is a compiled rule, giving the next state of a cell for each of the 512 possible states of its neighborhood.
+type Rule struct {
+	name  string
+	table [512]byte
+
+	// Rules with B0 would turn the whole background on at once, so like Golly, the field is stored complemented
+	// whenever that happens, and the background is always off. steps holds the table to use for each state of the
+	// background, giving the next state as stored, and backgrounds holds the state of the background afterwards.
+	steps       [2][512]byte
+	backgrounds [2]byte
+}
+
+// strobe fills in the steps and backgrounds of the rule from its table. For rules without B0, the background stays
+// off, and the steps for it are just the table.
+func (r *Rule) strobe() {
+	for bg := range r.backgrounds {
+		mask := bg * 0x1ff
+		r.backgrounds[bg] = r.table[mask]
+		for hood := range r.table {
+			r.steps[bg][hood] = r.table[hood^mask] ^ r.backgrounds[bg]
+		}
+	}
+}
+
+// String returns the rulestring the rule was parsed from.
+func (r *Rule) String() string {
+	return r.name
+}
+
+// ParseRule compiles a rule in birth/survival notation, such as B3/S23, optionally using Hensel notation to restrict
+// counts to particular neighborhoods, such as B2n3/S23-q. Rules ending in V, such as B13/S024V, only count the four
+// orthogonal neighbors. Rules may include B0.
+func ParseRule(rulestring string) (*Rule, error) {
+	r := &Rule{name: strings.TrimSpace(rulestring)}
+	if strings.HasSuffix(strings.ToUpper(r.name), "V") {
+		return parseVonNeumann(r)
+	}
+	born, survive, found := strings.Cut(r.name, "/")
+	if !found || len(born) == 0 || len(survive) == 0 || strings.ToUpper(born[:1]) != "B" || strings.ToUpper(survive[:1]) != "S" {
+		return nil, fmt.Errorf("Malformed rule - must take the form 'B#/S#': %q", rulestring)
+	}
+
+	bornHoods, err := parseConditions(born[1:])
+	if err != nil {
+		return nil, fmt.Errorf("Malformed rule - %v: %q", err, rulestring)
+	}
+	surviveHoods, err := parseConditions(survive[1:])
+	if err != nil {
+		return nil, fmt.Errorf("Malformed rule - %v: %q", err, rulestring)
+	}
+
+	for hood := range r.table {
+		if hood&center == 0 && bornHoods[hood&neighbors] || hood&center != 0 && surviveHoods[hood&neighbors] {
+			r.table[hood] = 1
+		}
+	}
+	r.strobe()
+	return r, nil
+}
+
+// parseVonNeumann compiles a von Neumann rule, where the counts run from 0 to 4 and the diagonal neighbors are
+// ignored entirely.
+func parseVonNeumann(r *Rule) (*Rule, error) {
+	malformed := fmt.Errorf("Malformed rule - must take the form 'B#/S#V' with counts from 0 to 4: %q", r.name)
+	born, survive, found := strings.Cut(strings.ToUpper(strings.TrimSuffix(r.name[:len(r.name)-1], "/")), "/")
+	if !found || !strings.HasPrefix(born, "B") || !strings.HasPrefix(survive, "S") {
+		return nil, malformed
+	}
+
+	var bornCounts, surviveCounts [5]bool
+	for _, c := range born[1:] {
+		if c < '0' || c > '4' {
+			return nil, malformed
+		}
+		bornCounts[c-'0'] = true
+	}
+	for _, c := range survive[1:] {
+		if c < '0' || c > '4' {
+			return nil, malformed
+		}
+		surviveCounts[c-'0'] = true
+	}
+
+	for hood := range r.table {
+		count := bits.OnesCount(uint(hood & orthogonal))
+		if hood&center == 0 && bornCounts[count] || hood&center != 0 && surviveCounts[count] {
+			r.table[hood] = 1
+		}
+	}
+	r.strobe()
+	return r, nil
+}
+
+// parseConditions returns which neighborhoods (without their center cell) match one half of a rulestring, such as
+// 2n3 or 23-q.
+func parseConditions(spec string) ([512]bool, error) {
+	var matches [512]bool
+	for i := 0; i < len(spec); {
+		if spec[i] < '0' || spec[i] > '8' {
+			return matches, fmt.Errorf("unexpected character '%c'", spec[i])
+		}
+		count := int(spec[i] - '0')
+		i++
+
+		// A minus sign means the letters that follow are excluded rather than included.
+		negate := false
+		if i < len(spec) && spec[i] == '-' {
+			negate = true
+			i++
+		}
+		start := i
+		for i < len(spec) && spec[i] >= 'a' && spec[i] <= 'z' {
+			if !strings.ContainsRune(lettersFor(count), rune(spec[i])) {
+				return matches, fmt.Errorf("'%c' is not valid for %d neighbors", spec[i], count)
+			}
+			i++
+		}
+		chosen := spec[start:i]
+		if negate && chosen == "" {
+			return matches, fmt.Errorf("expected letters after '-'")
+		}
+
+		for hood := range matches {
+			if hood&center != 0 || bits.OnesCount(uint(hood)) != count {
+				continue
+			}
+			if chosen == "" || strings.IndexByte(chosen, letters[hood]) >= 0 != negate {
+				matches[hood] = true
+			}
+		}
+	}
+	return matches, nil
+}
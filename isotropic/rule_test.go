@@ -0,0 +1,138 @@
+package isotropic
+
+import (
+	"math/bits"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/rle"
+)
+
+func TestParseRule(t *testing.T) {
+	Convey("When parsing a totalistic rule", t, func() {
+		r, err := ParseRule("B3/S23")
+		So(err, ShouldBeNil)
+
+		Convey("It should match Conway's Game of Life for every neighborhood", func() {
+			for hood, next := range r.table {
+				count := bits.OnesCount(uint(hood & neighbors))
+				expected := count == 3 || hood&center != 0 && count == 2
+				So(next == 1, ShouldEqual, expected)
+			}
+		})
+
+		Convey("It should match the same rule with every letter spelled out", func() {
+			spelled, err := ParseRule("B3ceaiknjqry/S2ceaikn3ceaiknjqry")
+			So(err, ShouldBeNil)
+			So(spelled.table, ShouldEqual, r.table)
+		})
+	})
+
+	Convey("When parsing a non-totalistic rule", t, func() {
+		r, err := ParseRule("B2n3/S23-q")
+		So(err, ShouldBeNil)
+
+		Convey("It should only include the neighborhoods named by the letters", func() {
+			births, survivals := 0, 0
+			for hood, next := range r.table {
+				if next == 0 {
+					continue
+				}
+				if hood&center == 0 {
+					births++
+				} else {
+					survivals++
+				}
+			}
+
+			// 2n has two orientations, and 3q has eight.
+			So(births, ShouldEqual, 2+56)
+			So(survivals, ShouldEqual, 28+56-8)
+		})
+
+		Convey("It should be isotropic", func() {
+			for hood, next := range r.table {
+				for _, sym := range symmetries(hood) {
+					So(r.table[sym], ShouldEqual, next)
+				}
+			}
+		})
+	})
+
+	Convey("Malformed rules should return errors", t, func() {
+		for _, rule := range []string{"", "B3", "S23/B3", "B3/S2z", "B2-/S23", "B9/S23", "B3/S2x3"} {
+			_, err := ParseRule(rule)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestNext(t *testing.T) {
+	Convey("Given a glider in a model following a non-totalistic rule which includes Life's glider", t, func() {
+		f, err := rle.Unmarshal(`x = 3, y = 3, rule = B3/S23-q
+bo$2bo$3o!`)
+		So(err, ShouldBeNil)
+		m := New(8, 8)
+		So(m.SetRule(f.Rule), ShouldBeNil)
+		m.Ingest(f)
+
+		Convey("It should follow the rule from the pattern", func() {
+			So(m.rule.String(), ShouldEqual, "B3/S23-q")
+		})
+
+		Convey("It should move one cell diagonally every four generations, wrapping around the edges", func() {
+			start := m.Export()
+			for i := 0; i < 4*8; i++ {
+				m.Next()
+			}
+			So(m.Export().Field, ShouldResemble, start.Field)
+
+			for i := 0; i < 4; i++ {
+				m.Next()
+			}
+			So(m.Export().Field, ShouldResemble, start.Crop(-1, -1, 8, 8).Field)
+		})
+	})
+
+	Convey("Given a pattern following rules with B0", t, func() {
+		f, err := rle.Unmarshal(`x = 3, y = 3
+bo$2bo$3o!`)
+		So(err, ShouldBeNil)
+
+		for _, rule := range []string{"B0123478/S01234678", "B03/S23", "B02c/S1e8", "B013/S02V"} {
+			m := New(32, 32)
+			m.SetRule(rule)
+			m.Ingest(f)
+
+			Convey("It should match the rule applied directly to every cell under "+rule, func() {
+				expected := m.Export()
+				for i := 0; i < 6; i++ {
+					m.Next()
+					expected = applyTable(m.rule, expected)
+					So(m.Export().Field, ShouldResemble, expected.Field)
+
+					// The pattern can't have reached the corner yet, so it should still be background, stored as off.
+					So(m.field[0], ShouldEqual, 0)
+				}
+			})
+		}
+	})
+}
+
+// applyTable evolves a field one generation by looking up the neighborhood of each cell in a rule's table.
+func applyTable(r *Rule, f *rle.RLEField) *rle.RLEField {
+	next := rle.New(f.Width, f.Height)
+	for y := 0; y < f.Height; y++ {
+		for x := 0; x < f.Width; x++ {
+			hood := 0
+			for bit := 0; bit < 9; bit++ {
+				if f.Field[(y+bit/3-1+f.Height)%f.Height][(x+bit%3-1+f.Width)%f.Width] {
+					hood |= 1 << bit
+				}
+			}
+			next.Field[y][x] = r.table[hood] == 1
+		}
+	}
+	return next
+}
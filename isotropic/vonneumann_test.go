@@ -0,0 +1,77 @@
+package isotropic
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/rle"
+)
+
+func TestVonNeumann(t *testing.T) {
+	Convey("Von Neumann rules only count orthogonal neighbors", t, func() {
+		r, err := ParseRule("B1/SV")
+		So(err, ShouldBeNil)
+		So(r.table[n], ShouldEqual, 1)
+		So(r.table[e], ShouldEqual, 1)
+		So(r.table[nw], ShouldEqual, 0)
+		So(r.table[n|nw|ne], ShouldEqual, 1)
+		So(r.table[n|s], ShouldEqual, 0)
+	})
+
+	Convey("Malformed von Neumann rules should return errors", t, func() {
+		for _, rule := range []string{"B5/S23V", "B1/S5V", "B1V", "B2a/S1V"} {
+			_, err := ParseRule(rule)
+			So(err, ShouldNotBeNil)
+		}
+	})
+
+	Convey("Under B1234/S01234V, a single cell grows into a diamond", t, func() {
+		f, err := rle.Unmarshal(`x = 1, y = 1, rule = B1234/S01234V
+o!`)
+		So(err, ShouldBeNil)
+		m := New(11, 11)
+		So(m.SetRule(f.Rule), ShouldBeNil)
+		m.Ingest(f)
+		for i := 0; i < 3; i++ {
+			m.Next()
+		}
+		e := m.Export()
+		for y, row := range e.Field {
+			for x, col := range row {
+				dx, dy := x-5, y-5
+				if dx < 0 {
+					dx = -dx
+				}
+				if dy < 0 {
+					dy = -dy
+				}
+				So(col, ShouldEqual, dx+dy <= 3)
+			}
+		}
+	})
+
+	Convey("Under the parity rule B13/S13V, any pattern is copied to four places", t, func() {
+		// Each cell becomes the exclusive or of its four neighbors, so after 2^k generations, a pattern smaller than
+		// 2^k cells across becomes four copies of itself 2^k cells away in each direction.
+		f, err := rle.Unmarshal(`x = 3, y = 3, rule = B13/S13V
+bo$2bo$3o!`)
+		So(err, ShouldBeNil)
+		m := New(16, 16)
+		So(m.SetRule(f.Rule), ShouldBeNil)
+		m.Ingest(f)
+		start := m.Export()
+		for i := 0; i < 4; i++ {
+			m.Next()
+		}
+
+		left, right := start.Crop(4, 0, 16, 16), start.Crop(-4, 0, 16, 16)
+		up, down := start.Crop(0, 4, 16, 16), start.Crop(0, -4, 16, 16)
+		e := m.Export()
+		for y, row := range e.Field {
+			for x, col := range row {
+				So(col, ShouldEqual, left.Field[y][x] != right.Field[y][x] != up.Field[y][x] != down.Field[y][x])
+			}
+		}
+	})
+}
@@ -2,7 +2,12 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -11,48 +16,404 @@ import (
 	"github.com/makyo/gogol/abrashchangelist"
 	"github.com/makyo/gogol/abrashstruct"
 	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/colorlife"
+	"github.com/makyo/gogol/continuous"
+	"github.com/makyo/gogol/elementary"
+	"github.com/makyo/gogol/generations"
+	"github.com/makyo/gogol/hex"
+	"github.com/makyo/gogol/isotropic"
+	"github.com/makyo/gogol/life3d"
+	"github.com/makyo/gogol/ltl"
+	"github.com/makyo/gogol/margolus"
 	"github.com/makyo/gogol/naive1d"
 	"github.com/makyo/gogol/naive2d"
 	"github.com/makyo/gogol/prestafford1"
 	"github.com/makyo/gogol/prestafford2"
+	"github.com/makyo/gogol/render"
+	"github.com/makyo/gogol/rle"
+	"github.com/makyo/gogol/ruleloader"
 	"github.com/makyo/gogol/scholes"
+	"github.com/makyo/gogol/stats"
+	"github.com/makyo/gogol/stochastic"
+	"github.com/makyo/gogol/triangular"
+	"github.com/makyo/gogol/turmite"
+	"github.com/makyo/gogol/wireworld"
 )
 
 type tickMsg time.Time
 
 type model struct {
 	base base.Model
+
+	// err is the error which made the UI quit, if any.
+	err error
 }
 
 var (
-	algoFlag = flag.String("algo", "naive1d", "Which algorithm to use (naive1d, naive2d, scholes, abrashstruct, abrash, abrash1d, abrashchangelist, prestafford1, prestafford2)")
-	width    = 10
-	height   = 10
+	algoFlag    = flag.String("algo", "naive1d", algoUsage)
+	ruleFlag    = flag.String("rule", "", ruleUsage)
+	patternFlag = flag.String("pattern", "", "RLE file (or RLE3 file, for life3d), or PNG, GIF, or JPEG image of a board, to load instead of a random field")
+	rulesFlag   = flag.String("rules", ruleloader.DefaultDir, rulesUsage)
+	singleFlag  = flag.Bool("single", false, "Start from a single living cell instead of a random field, for algorithms which support it")
+	depthFlag   = flag.Int("depth", life3d.DefaultDepth, depthUsage)
+	seedFlag    = flag.Int64("seed", 0, "Seed for the random field and random updates (0 picks one from the time)")
+	recordFlag  = flag.String("record", "", "File to record the population, births, deaths, and bounding box of each generation to when quitting, as JSON if it ends in .json and CSV otherwise")
+	heatFlag    = flag.String("heat", stats.Alive, "What the heat map shown on H counts for each cell, from when it is first shown: births, deaths, or alive")
+	heatMapFlag = flag.String("heatmap", "", "File to write the heat map to when quitting, as CSV if it ends in .csv and PNG otherwise")
+	wrapUpdates = updateFlags(flag.CommandLine)
+	imageFlags  = importFlags(flag.CommandLine)
+	recorder    *stats.Recorder
+	heat        *stats.HeatMap
+	showHeat    bool
+	recordHeat  bool
+	pattern     *rle.RLEField
+	pattern3    *rle.RLE3Field
+	width       = 10
+	height      = 10
+)
+
+// commands holds the subcommands which run without the UI, keyed by name.
+var commands = map[string]func(args []string) error{
+	"gif":      gifCommand,
+	"svg":      svgCommand,
+	"png":      pngCommand,
+	"analyze":  analyzeCommand,
+	"census":   censusCommand,
+	"lifespan": lifespanCommand,
+	"heatmap":  heatMapCommand,
+	"record":   recordCommand,
+	"search":   searchCommand,
+}
+
+// Usage for the flags which choose the algorithm and rule, which are shared between the UI and the subcommands. The
+// algorithms and the rules each follows are listed in the README.
+const (
+	algoUsage  = "Which algorithm to use (see the README for the list)"
+	ruleUsage  = "Rulestring for algorithms which support rules other than B3/S23 (see the README for each algorithm's rules)"
+	rulesUsage = "Directory to load .rule files from for the ruleloader algorithm"
+	depthUsage = "Number of layers for the life3d algorithm"
 )
 
-func getModel(width, height int) model {
-	m := model{}
-	switch *algoFlag {
+// algoOptions holds the settings which only some algorithms take when they are created.
+type algoOptions struct {
+	// rules is the directory the ruleloader algorithm loads .rule files from.
+	rules string
+
+	// depth is the number of layers for the life3d algorithm.
+	depth int
+}
+
+// defaultAlgoOptions are the settings used when the flags don't say otherwise.
+var defaultAlgoOptions = algoOptions{rules: ruleloader.DefaultDir, depth: life3d.DefaultDepth}
+
+// newModel creates a model using the named algorithm.
+func newModel(algo string, width, height int, opts algoOptions) (base.Model, error) {
+	switch algo {
 	case "naive1d":
-		m.base = naive1d.New(width, height)
+		return naive1d.New(width, height), nil
 	case "naive2d":
-		m.base = naive2d.New(width, height)
+		return naive2d.New(width, height), nil
 	case "scholes":
-		m.base = scholes.New(width, height)
+		return scholes.New(width, height), nil
 	case "abrashstruct":
-		m.base = abrashstruct.New(width, height)
+		return abrashstruct.New(width, height), nil
 	case "abrash":
-		m.base = abrash.New(width, height)
+		return abrash.New(width, height), nil
 	case "abrash1d":
-		m.base = abrash1d.New(width, height)
+		return abrash1d.New(width, height), nil
 	case "abrashchangelist":
-		m.base = abrashchangelist.New(width, height)
+		return abrashchangelist.New(width, height), nil
 	case "prestafford1":
-		m.base = prestafford1.New(width, height)
+		return prestafford1.New(width, height), nil
 	case "prestafford2":
-		m.base = prestafford2.New(width, height)
+		return prestafford2.New(width, height), nil
+	case "isotropic":
+		return isotropic.New(width, height), nil
+	case "generations":
+		return generations.New(width, height), nil
+	case "ltl":
+		return ltl.New(width, height), nil
+	case "hex":
+		return hex.New(width, height), nil
+	case "wireworld":
+		return wireworld.New(width, height), nil
+	case "ruleloader":
+		return ruleloader.New(width, height, opts.rules), nil
+	case "elementary":
+		return elementary.New(width, height), nil
+	case "margolus":
+		return margolus.New(width, height), nil
+	case "colorlife":
+		return colorlife.New(width, height), nil
+	case "turmite":
+		return turmite.New(width, height), nil
+	case "continuous":
+		return continuous.New(width, height), nil
+	case "life3d":
+		return life3d.New(width, height, opts.depth), nil
+	case "triangular":
+		return triangular.New(width, height), nil
+	}
+	return nil, fmt.Errorf("Unknown algorithm: %q", algo)
+}
+
+// loadPattern reads an RLE file from disk, or an image of a board, which is turned into a field as the given options
+// say.
+func loadPattern(path string, opts render.ImportOptions) (*rle.RLEField, error) {
+	if isImage(path) {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return render.Decode(file, opts)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
 	}
-	return m
+	return rle.Unmarshal(string(contents))
+}
+
+// isImage returns whether the file at the given path is an image which render.Decode can read.
+func isImage(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".gif", ".jpg", ".jpeg":
+		return true
+	}
+	return false
+}
+
+// polarities maps the values of the polarity flag to which cells of an image are alive.
+var polarities = map[string]render.Polarity{
+	"auto":  render.Auto,
+	"light": render.LightAlive,
+	"dark":  render.DarkAlive,
+}
+
+// importFlags adds the flags for turning an image of a board into a field (see render.Import) to a flag set. It returns
+// a function which builds the options once the flags are parsed.
+func importFlags(fs *flag.FlagSet) func() (render.ImportOptions, error) {
+	threshold := fs.Float64("threshold", 0.5, "Brightness from 0 to 1 which separates light cells from dark in an image pattern")
+	polarity := fs.String("polarity", "auto", "Which cells of an image pattern are alive: light, dark, or auto, whichever are fewer")
+	cellSize := fs.Int("image-cell", 0, "Size of each cell of an image pattern in pixels (0 detects the layout from the image)")
+	gridWidth := fs.Int("image-grid", 0, "Width of the grid lines between the cells of an image pattern in pixels, with -image-cell")
+	left := fs.Int("image-left", 0, "Pixels to the left of the first whole cell of an image pattern, with -image-cell")
+	top := fs.Int("image-top", 0, "Pixels above the first whole cell of an image pattern, with -image-cell")
+	return func() (render.ImportOptions, error) {
+		p, ok := polarities[*polarity]
+		if !ok {
+			return render.ImportOptions{}, fmt.Errorf("Unknown polarity: %q (must be auto, light, or dark)", *polarity)
+		}
+		return render.ImportOptions{
+			Threshold: *threshold,
+			Polarity:  p,
+			CellSize:  *cellSize,
+			GridWidth: *gridWidth,
+			Left:      *left,
+			Top:       *top,
+		}, nil
+	}
+}
+
+// isRLE3 returns whether the file at the given path holds a three-dimensional pattern, which Golly saves as .rle3.
+func isRLE3(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".rle3")
+}
+
+// loadPattern3 reads an RLE3 file from disk.
+func loadPattern3(path string) (*rle.RLE3Field, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return rle.Unmarshal3(string(contents))
+}
+
+// ingest3 sets a model to a three-dimensional pattern. Models which don't implement base.Volumetric can't hold one.
+func ingest3(m base.Model, f *rle.RLE3Field) error {
+	v, ok := m.(base.Volumetric)
+	if !ok {
+		return fmt.Errorf("This algorithm doesn't support three-dimensional patterns")
+	}
+	if err := applyRule(m, f.Rule); err != nil {
+		return err
+	}
+	v.Ingest3(f)
+	return nil
+}
+
+// applyRule sets the rule for a model. Models which don't implement base.Ruled only follow Conway's Game of Life.
+func applyRule(m base.Model, rule string) error {
+	if rule == "" {
+		return nil
+	}
+	if r, ok := m.(base.Ruled); ok {
+		return r.SetRule(rule)
+	}
+	if strings.EqualFold(rule, "B3/S23") {
+		return nil
+	}
+	return fmt.Errorf("This algorithm only supports B3/S23, not %q", rule)
+}
+
+// updateFlags adds the flags for updating cells other than all at once (see the stochastic package) to a flag set. It
+// returns a function which wraps a model to be updated as the flags say, or returns it as it is if they are unset.
+func updateFlags(fs *flag.FlagSet) func(m base.Model, seed int64) (base.Model, error) {
+	mode := fs.String("update", stochastic.Synchronous, "How cells are updated, for algorithms which support it: synchronous, all at once, or sequential, one at a time in a random order")
+	probability := fs.Float64("p", 1, "Chance that each cell is updated each generation, for algorithms which support it")
+	noise := fs.Float64("noise", 0, "Chance that each cell the rule leaves alive or dead is flipped, for algorithms which support it")
+	return func(m base.Model, seed int64) (base.Model, error) {
+		opts := stochastic.DefaultOptions()
+		if *mode == opts.Mode && *probability == opts.Probability && *noise == opts.Noise {
+			return m, nil
+		}
+		opts.Mode = *mode
+		opts.Probability = *probability
+		opts.Noise = *noise
+		opts.Seed = seed
+		return stochastic.New(m, opts)
+	}
+}
+
+// algoFlags holds the flags which choose the algorithm a subcommand runs and the rule it follows, and how it reads
+// image patterns.
+type algoFlags struct {
+	algo, rule, rules *string
+	depth             *int
+	image             func() (render.ImportOptions, error)
+}
+
+// newAlgoFlags adds the flags which choose the algorithm and rule to a flag set, using the given algorithm by default,
+// along with the flags for image patterns.
+func newAlgoFlags(fs *flag.FlagSet, algo string) algoFlags {
+	return algoFlags{
+		algo:  fs.String("algo", algo, algoUsage),
+		rule:  fs.String("rule", "", ruleUsage),
+		rules: fs.String("rules", ruleloader.DefaultDir, rulesUsage),
+		depth: fs.Int("depth", life3d.DefaultDepth, depthUsage),
+		image: importFlags(fs),
+	}
+}
+
+// start creates a model of the given size as the flags say (see startModel).
+func (a algoFlags) start(width, height int, seed int64, args []string) (base.Model, error) {
+	imports, err := a.image()
+	if err != nil {
+		return nil, err
+	}
+	return startModel(*a.algo, *a.rule, algoOptions{rules: *a.rules, depth: *a.depth}, imports, width, height, seed, args)
+}
+
+// modelFlags adds the flags shared by the subcommands which run a model without the UI, either from a pattern or from a
+// random field, to a flag set. It returns a function which starts the model once the flags are parsed, wrapped to be
+// updated as the update flags say. Fields are the given number of cells across and down unless the flags say
+// otherwise.
+func modelFlags(fs *flag.FlagSet, size int) func() (base.Model, error) {
+	algo := newAlgoFlags(fs, "abrash")
+	width := fs.Int("width", size, "Width of the field in cells")
+	height := fs.Int("height", size, "Height of the field in cells")
+	seed := fs.Int64("seed", 0, "Seed for the random field, if no pattern is given, and for random updates")
+	single := fs.Bool("single", false, "Start from a single living cell instead of a random field, for algorithms which support it")
+	wrapUpdates := updateFlags(fs)
+	return func() (base.Model, error) {
+		m, err := algo.start(*width, *height, *seed, fs.Args())
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := m.(base.Seedable); ok && *single && fs.NArg() == 0 {
+			s.PopulateSingle()
+		}
+		return wrapUpdates(m, *seed)
+	}
+}
+
+// startModel creates a model using the named algorithm and either ingests the pattern file given in args, reading
+// images as the import options say, or, if there is none, populates it at random using the given seed. If a rule is
+// given, it overrides any rule in the pattern.
+func startModel(algo, rule string, opts algoOptions, imports render.ImportOptions, width, height int, seed int64, args []string) (base.Model, error) {
+	m, err := newModel(algo, width, height, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) > 0 && isRLE3(args[0]) {
+		f, err := loadPattern3(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if err := ingest3(m, f); err != nil {
+			return nil, err
+		}
+	} else if len(args) > 0 {
+		f, err := loadPattern(args[0], imports)
+		if err != nil {
+			return nil, err
+		}
+		patternRule := rule
+		if patternRule == "" {
+			patternRule = f.Rule
+		}
+		if err := applyRule(m, patternRule); err != nil {
+			return nil, err
+		}
+		m.Ingest(f)
+	} else {
+		rand.Seed(seed)
+		m.Populate()
+	}
+	if err := applyRule(m, rule); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// getModel creates a model using the algorithm, rule, and update flags which fills a screen of the given size.
+func getModel(width, height int) (model, error) {
+	opts := algoOptions{rules: *rulesFlag, depth: *depthFlag}
+	b, err := newModel(*algoFlag, width, height, opts)
+	if err != nil {
+		return model{}, err
+	}
+
+	// Models which draw each cell wider than a single column need fewer cells to fill the screen.
+	if w, ok := b.(base.Wide); ok && w.CellWidth() > 1 {
+		if b, err = newModel(*algoFlag, width/w.CellWidth(), height, opts); err != nil {
+			return model{}, err
+		}
+	}
+	if err := applyRule(b, *ruleFlag); err != nil {
+		return model{}, err
+	}
+	if b, err = wrapUpdates(b, *seedFlag); err != nil {
+		return model{}, err
+	}
+	return model{base: b}, nil
+}
+
+// startField fills a new model's field with the pattern given by the pattern flag, following the pattern's rule unless
+// the rule flag gives one, or else starts it from a single cell or at random.
+func startField(m base.Model) error {
+	switch {
+	case pattern != nil:
+		if *ruleFlag == "" {
+			if err := applyRule(m, pattern.Rule); err != nil {
+				return err
+			}
+		}
+		m.Ingest(pattern)
+	case pattern3 != nil:
+		if err := ingest3(m, pattern3); err != nil {
+			return err
+		}
+		return applyRule(m, *ruleFlag)
+	default:
+		if s, ok := m.(base.Seedable); ok && *singleFlag {
+			s.PopulateSingle()
+		} else {
+			m.Populate()
+		}
+	}
+	return nil
 }
 
 // tick updates the model every 1/10 second.
@@ -81,7 +442,48 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Regenerate the field on Ctrl+R
 		case "ctrl+r":
-			m = getModel(width, height)
+			next, err := getModel(width, height)
+			if err != nil {
+				m.err = err
+				return m, tea.Quit
+			}
+			m = next
+			startRecording(m.base)
+			return m, nil
+
+		// Run backwards (or forwards again) on B, for models which can
+		case "b":
+			if r, ok := m.base.(base.Reversible); ok {
+				if err := r.Reverse(); err != nil {
+					m.err = err
+					return m, tea.Quit
+				}
+			}
+			return m, nil
+
+		// Move through the layers of the field on [ and ], and draw them all at once on P, for models which have them
+		case "[", "]":
+			if l, ok := m.base.(base.Layered); ok {
+				if msg.String() == "[" {
+					l.MoveLayer(-1)
+				} else {
+					l.MoveLayer(1)
+				}
+			}
+			return m, nil
+		case "p":
+			if l, ok := m.base.(base.Layered); ok {
+				l.ToggleProjection()
+			}
+			return m, nil
+
+		// Show the heat map in place of the field (or the field again) on H
+		case "h":
+			showHeat = !showHeat
+			if showHeat && !recordHeat {
+				recordHeat = true
+				heat.Record(m.base)
+			}
 			return m, nil
 		}
 
@@ -100,14 +502,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Reset the field to the correct size
 		width = msg.Width
 		height = msg.Height
-		m = getModel(width, height)
-		m.base.Populate()
+		next, err := getModel(width, height)
+		if err != nil {
+			m.err = err
+			return m, tea.Quit
+		}
+		m = next
+		if err := startField(m.base); err != nil {
+			m.err = err
+			return m, tea.Quit
+		}
+		startRecording(m.base)
 
 	// Tick messages
 	case tickMsg:
 
 		// Evolve the next generation
 		m.base.Next()
+		recordGeneration(m.base)
 		return m, tick()
 	}
 	return m, nil
@@ -115,13 +527,101 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View builds the entire screen's worth of cells to be printed by returning a • for a living cell or a space for a dead cell.
 func (m model) View() string {
+	if showHeat {
+		return heat.String()
+	}
 	return m.base.String()
 }
 
+// startRecording clears the heat map and any recording for a new field, and records its first generation.
+func startRecording(m base.Model) {
+	heat.Reset()
+	heat.CellWidth = 1
+	if w, ok := m.(base.Wide); ok {
+		heat.CellWidth = w.CellWidth()
+	}
+	heat.RowShift = nil
+	if s, ok := m.(base.Sheared); ok {
+		heat.RowShift = s.RowShift
+	}
+	if recorder != nil {
+		recorder.Reset()
+	}
+	recordGeneration(m)
+}
+
+// recordGeneration adds the current generation to any recording, and to the heat map once it has been shown or is to be
+// written out, which saves exporting the field every generation otherwise.
+func recordGeneration(m base.Model) {
+	if recordHeat {
+		heat.Record(m)
+	}
+	if recorder != nil {
+		recorder.Record(m)
+	}
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
 	flag.Parse()
-	p := tea.NewProgram(getModel(width, height), tea.WithAltScreen(), tea.WithMouseAllMotion())
-	if _, err := p.Run(); err != nil {
+	if *seedFlag == 0 {
+		*seedFlag = time.Now().UnixNano()
+	}
+	rand.Seed(*seedFlag)
+	var err error
+	if *patternFlag != "" && isRLE3(*patternFlag) {
+		if pattern3, err = loadPattern3(*patternFlag); err != nil {
+			log.Fatal(err)
+		}
+	} else if *patternFlag != "" {
+		imports, err := imageFlags()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if pattern, err = loadPattern(*patternFlag, imports); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *recordFlag != "" {
+		recorder = stats.New()
+	}
+	if heat, err = stats.NewHeatMap(*heatFlag); err != nil {
+		log.Fatal(err)
+	}
+	recordHeat = *heatMapFlag != ""
+
+	// The UI starts again with a field to fill the screen as soon as it knows its size, but starting a small one first
+	// reports any problem with the algorithm, rule, or pattern before the UI takes over the terminal.
+	initial, err := getModel(width, height)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := startField(initial.base); err != nil {
 		log.Fatal(err)
 	}
+	p := tea.NewProgram(initial, tea.WithAltScreen(), tea.WithMouseAllMotion())
+	final, err := p.Run()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if final.(model).err != nil {
+		log.Fatal(final.(model).err)
+	}
+	if recorder != nil {
+		if err := writeRecording(*recordFlag, recorder); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *heatMapFlag != "" {
+		if err := writeHeatMap(*heatMapFlag, heat, 4); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
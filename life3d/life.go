@@ -0,0 +1,219 @@
+package life3d
+
+import (
+	"image/color"
+	"math/rand"
+	"strings"
+
+	"github.com/makyo/gogol/render"
+	"github.com/makyo/gogol/rle"
+)
+
+// The field is a three-dimensional torus, made up of layers stacked one behind the other. Only one layer is drawn at a
+// time, or else all of them at once, flattened so that each cell shows the frontmost living cell behind it.
+
+// DefaultDepth is the usual number of layers.
+const DefaultDepth = 16
+
+type model struct {
+	width  int
+	height int
+	depth  int
+	field  []byte
+	rule   *Rule
+
+	// layer is the layer being drawn, and projected whether to draw every layer at once instead.
+	layer     int
+	projected bool
+}
+
+// index returns the position of the given cell in the field, wrapping around the edges.
+func (m *model) index(x, y, z int) int {
+	x = (x%m.width + m.width) % m.width
+	y = (y%m.height + m.height) % m.height
+	z = (z%m.depth + m.depth) % m.depth
+	return (z*m.height+y)*m.width + x
+}
+
+// Next evolves the field one generation. Neighbors are counted by summing each cell with those on either side of it
+// along each axis in turn, which gives the sum of the 3x3x3 cube around it, then taking away the cell itself.
+func (m *model) Next() {
+	sums := m.field
+	for _, d := range [3][3]int{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+		next := make([]byte, len(sums))
+		for z := 0; z < m.depth; z++ {
+			for y := 0; y < m.height; y++ {
+				for x := 0; x < m.width; x++ {
+					next[m.index(x, y, z)] = sums[m.index(x-d[0], y-d[1], z-d[2])] + sums[m.index(x, y, z)] + sums[m.index(x+d[0], y+d[1], z+d[2])]
+				}
+			}
+		}
+		sums = next
+	}
+	for i, state := range m.field {
+		sums[i] = m.rule.next(state, sums[i]-state)
+	}
+	m.field = sums
+}
+
+// Populate fills the field at random, with a fifth of the cells alive.
+func (m *model) Populate() {
+	for i := range m.field {
+		m.field[i] = 0
+		if rand.Intn(5) == 0 {
+			m.field[i] = 1
+		}
+	}
+}
+
+// SetRule sets the rule the model follows from a rulestring such as 4555 or 3D4,5/5.
+func (m *model) SetRule(rulestring string) error {
+	r, err := ParseRule(rulestring)
+	if err != nil {
+		return err
+	}
+	m.rule = r
+	return nil
+}
+
+// MoveLayer moves the layer being drawn by the given number of layers, wrapping around front to back.
+func (m *model) MoveLayer(by int) {
+	m.layer = ((m.layer+by)%m.depth + m.depth) % m.depth
+}
+
+// ToggleProjection switches between drawing a single layer and drawing every layer at once.
+func (m *model) ToggleProjection() {
+	m.projected = !m.projected
+}
+
+// Ingest sets the layer being drawn to the given two-dimensional pattern.
+func (m *model) Ingest(f *rle.RLEField) {
+	startX := (m.width - f.Width) / 2
+	startY := (m.height - f.Height) / 2
+	for y, row := range f.Field {
+		for x, col := range row {
+			if col {
+				m.field[m.index(x+startX, y+startY, m.layer)] = 1
+			}
+		}
+	}
+}
+
+// Ingest3 sets the field to the given three-dimensional pattern, centered in every direction.
+func (m *model) Ingest3(f *rle.RLE3Field) {
+	startX := (m.width - f.Width) / 2
+	startY := (m.height - f.Height) / 2
+	startZ := (m.depth - f.Depth) / 2
+	for z, layer := range f.Cells {
+		for y, row := range layer {
+			for x, col := range row {
+				if col {
+					m.field[m.index(x+startX, y+startY, z+startZ)] = 1
+				}
+			}
+		}
+	}
+}
+
+// alive returns whether the given cell is drawn as alive, which, when every layer is drawn at once, is whether any
+// cell behind it is alive.
+func (m *model) alive(x, y int) bool {
+	if !m.projected {
+		return m.field[m.index(x, y, m.layer)] == 1
+	}
+	for z := 0; z < m.depth; z++ {
+		if m.field[m.index(x, y, z)] == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleCell toggles the given cell in the layer being drawn.
+func (m *model) ToggleCell(x, y int) {
+	m.field[m.index(x, y, m.layer)] ^= 1
+}
+
+// Export returns the layer being drawn as a two-dimensional pattern or, when every layer is drawn at once, the
+// flattened field.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(m.rule.String())
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			f.Field[y][x] = m.alive(x, y)
+		}
+	}
+	return f
+}
+
+// Export3 returns the current state of the whole field.
+func (m *model) Export3() *rle.RLE3Field {
+	f := rle.New3(m.width, m.height, m.depth)
+	f.Rule = m.rule.String()
+	for i, c := range m.field {
+		f.Cells[i/(m.width*m.height)][i/m.width%m.height][i%m.width] = c == 1
+	}
+	return f
+}
+
+// Colors used for drawing cells: when every layer is drawn at once, cells fade from white at the front to blue at the
+// back, and when drawing a single layer, cells alive in the layers just in front of or behind it are shown in gray.
+var (
+	frontColor  = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	backColor   = color.RGBA{0x20, 0x40, 0xc0, 0xff}
+	nearbyColor = color.RGBA{0x60, 0x60, 0x60, 0xff}
+)
+
+// String builds the entire screen's worth of cells to be printed. When drawing a single layer, it returns a • for a
+// living cell, a gray · for a dead cell with a living cell just in front of or behind it, and a space for any other
+// dead cell. When drawing every layer at once, it returns a • for each frontmost living cell, colored by how far back
+// it is.
+func (m *model) String() string {
+	var frame strings.Builder
+	for y := 0; y < m.height; y++ {
+		if y > 0 {
+			frame.WriteString("\n")
+		}
+		for x := 0; x < m.width; x++ {
+			if !m.projected {
+				switch {
+				case m.field[m.index(x, y, m.layer)] == 1:
+					frame.WriteString("•")
+				case m.field[m.index(x, y, m.layer-1)] == 1 || m.field[m.index(x, y, m.layer+1)] == 1:
+					frame.WriteString(render.Colorize("·", nearbyColor))
+				default:
+					frame.WriteString(" ")
+				}
+				continue
+			}
+			z := 0
+			for z < m.depth && m.field[m.index(x, y, z)] == 0 {
+				z++
+			}
+			if z == m.depth {
+				frame.WriteString(" ")
+				continue
+			}
+			amount := 0.0
+			if m.depth > 1 {
+				amount = float64(z) / float64(m.depth-1)
+			}
+			frame.WriteString(render.Colorize("•", render.Fade(frontColor, backColor, amount)))
+		}
+	}
+	return frame.String()
+}
+
+// New creates a model of the given size and number of layers, following Bays' rule 4555 and drawing its middle layer.
+func New(width, height, depth int) *model {
+	r, _ := ParseRule("4555")
+	return &model{
+		width:  width,
+		height: height,
+		depth:  depth,
+		field:  make([]byte, width*height*depth),
+		rule:   r,
+		layer:  depth / 2,
+	}
+}
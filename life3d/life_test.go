@@ -0,0 +1,114 @@
+package life3d
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/render"
+	"github.com/makyo/gogol/rle"
+)
+
+func TestParseRule(t *testing.T) {
+	Convey("Rules in either notation should be written in Golly's", t, func() {
+		for rulestring, expected := range map[string]string{
+			"4555":        "3D4,5/5",
+			"Life5766":    "3D5,6,7/6",
+			"5,7,6,6":     "3D5,6,7/6",
+			"3D5..7/6":    "3D5,6,7/6",
+			"3d/2,10..12": "3D/2,10,11,12",
+			"3D4,5/5M":    "3D4,5/5",
+		} {
+			r, err := ParseRule(rulestring)
+			So(err, ShouldBeNil)
+			So(r.String(), ShouldEqual, expected)
+		}
+	})
+
+	Convey("Malformed rules should return errors", t, func() {
+		for _, rulestring := range []string{"", "455", "45556", "5466", "4505", "3D4,5", "3D4,5/0", "3D27/5", "3D7..5/5", "B3/S23"} {
+			_, err := ParseRule(rulestring)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestNext(t *testing.T) {
+	Convey("Each generation should match counting all 26 neighbors of every cell", t, func() {
+		m := New(8, 7, 6)
+		So(m.SetRule("3D2..6/3,4"), ShouldBeNil)
+		for i := range m.field {
+			m.field[i] = byte(rand.Intn(3) / 2)
+		}
+		for generation := 0; generation < 5; generation++ {
+			expected := make([]byte, len(m.field))
+			for z := 0; z < m.depth; z++ {
+				for y := 0; y < m.height; y++ {
+					for x := 0; x < m.width; x++ {
+						count := byte(0)
+						for dz := -1; dz <= 1; dz++ {
+							for dy := -1; dy <= 1; dy++ {
+								for dx := -1; dx <= 1; dx++ {
+									if dx != 0 || dy != 0 || dz != 0 {
+										count += m.field[m.index(x+dx, y+dy, z+dz)]
+									}
+								}
+							}
+						}
+						expected[m.index(x, y, z)] = m.rule.next(m.field[m.index(x, y, z)], count)
+					}
+				}
+			}
+			m.Next()
+			So(m.field, ShouldResemble, expected)
+		}
+	})
+}
+
+func TestModel(t *testing.T) {
+	Convey("Given a three-dimensional pattern", t, func() {
+		f, err := rle.Unmarshal3("3D version=1\nx=2 y=2 z=3 rule=5766\n2o$o/o/bo!")
+		So(err, ShouldBeNil)
+		m := New(10, 10, DefaultDepth)
+		So(m.SetRule(f.Rule), ShouldBeNil)
+		m.Ingest3(f)
+
+		Convey("It should be centered in every direction, along with its rule", func() {
+			So(m.rule.String(), ShouldEqual, "3D5,6,7/6")
+			So(m.field[m.index(4, 4, 6)], ShouldEqual, 1)
+			So(m.field[m.index(5, 4, 8)], ShouldEqual, 1)
+			So(m.String(), ShouldContainSubstring, "\n    "+render.Colorize("·", nearbyColor)+"•    \n")
+		})
+
+		Convey("It should survive a round trip", func() {
+			n := New(10, 10, DefaultDepth)
+			n.Ingest3(m.Export3())
+			So(n.field, ShouldResemble, m.field)
+		})
+
+		Convey("Layers can be drawn one at a time, or all at once", func() {
+			So(m.Export().Field[4][4], ShouldBeFalse)
+			So(m.Export().Field[4][5], ShouldBeTrue)
+			m.MoveLayer(-2)
+			So(m.layer, ShouldEqual, 6)
+			So(m.Export().Field[5][4], ShouldBeTrue)
+			m.MoveLayer(m.depth + 1)
+			So(m.layer, ShouldEqual, 7)
+			So(m.Export().Field[4][4], ShouldBeTrue)
+			m.ToggleProjection()
+			_, _, width, height := m.Export().BoundingBox()
+			So([]int{width, height}, ShouldResemble, []int{2, 2})
+		})
+
+		Convey("Two-dimensional patterns and toggled cells should go in the layer being drawn", func() {
+			m.MoveLayer(2)
+			m.ToggleCell(0, 0)
+			So(m.field[m.index(0, 0, 10)], ShouldEqual, 1)
+			g, err := rle.Unmarshal("x = 1, y = 1\no!")
+			So(err, ShouldBeNil)
+			m.Ingest(g)
+			So(m.field[m.index(4, 4, 10)], ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,102 @@
+package life3d
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rule is an outer totalistic rule in three dimensions, where each cell has 26 neighbors: those in the 3x3x3 cube
+// around it.
+type Rule struct {
+	born, survive [27]bool
+}
+
+// String returns the rule in the notation used by Golly's 3D.lua, such as 3D4,5/5.
+func (r *Rule) String() string {
+	list := func(counts *[27]bool) string {
+		var parts []string
+		for count, ok := range counts {
+			if ok {
+				parts = append(parts, strconv.Itoa(count))
+			}
+		}
+		return strings.Join(parts, ",")
+	}
+	return "3D" + list(&r.survive) + "/" + list(&r.born)
+}
+
+// next returns the next state of a cell, given its state and number of living neighbors.
+func (r *Rule) next(state byte, count byte) byte {
+	if state == 0 && r.born[count] || state == 1 && r.survive[count] {
+		return 1
+	}
+	return 0
+}
+
+// ParseRule parses a three-dimensional rule, either in Bays' notation, giving the least and most neighbors with which
+// a cell survives followed by the least and most with which one is born, such as 4555 or 5,7,6,6, or in the notation
+// used by Golly's 3D.lua, giving the counts for survival and then for birth, such as 3D4,5/5 or 3D5..7/6. Births with
+// no neighbors aren't supported.
+func ParseRule(rulestring string) (*Rule, error) {
+	r := &Rule{}
+	malformed := fmt.Errorf("Malformed rule - must take the form '####' or '3D#,#/#,#' with counts from 0 to 26: %q", rulestring)
+
+	spec := strings.ToUpper(strings.TrimSpace(rulestring))
+	if strings.HasPrefix(spec, "3D") {
+		survive, born, found := strings.Cut(strings.TrimSuffix(spec[2:], "M"), "/")
+		if !found || !parseCounts(survive, &r.survive) || !parseCounts(born, &r.born) || r.born[0] {
+			return nil, malformed
+		}
+		return r, nil
+	}
+
+	// Bays' rules are usually written as four digits, but counts over 9 need commas.
+	parts := strings.Split(strings.TrimPrefix(spec, "LIFE"), ",")
+	if len(parts) == 1 {
+		parts = strings.Split(parts[0], "")
+	}
+	if len(parts) != 4 {
+		return nil, malformed
+	}
+	var bounds [4]int
+	for i, part := range parts {
+		var err error
+		if bounds[i], err = strconv.Atoi(part); err != nil || bounds[i] < 0 || bounds[i] > 26 {
+			return nil, malformed
+		}
+	}
+	if bounds[0] > bounds[1] || bounds[2] > bounds[3] || bounds[2] == 0 {
+		return nil, malformed
+	}
+	for count := bounds[0]; count <= bounds[1]; count++ {
+		r.survive[count] = true
+	}
+	for count := bounds[2]; count <= bounds[3]; count++ {
+		r.born[count] = true
+	}
+	return r, nil
+}
+
+// parseCounts parses a list of counts separated by commas, where each is either a single count or a range such as
+// 5..7, into the given table.
+func parseCounts(spec string, counts *[27]bool) bool {
+	if spec == "" {
+		return true
+	}
+	for _, part := range strings.Split(spec, ",") {
+		low, high, found := strings.Cut(part, "..")
+		if !found {
+			high = low
+		}
+		from, err1 := strconv.Atoi(low)
+		to, err2 := strconv.Atoi(high)
+		if err1 != nil || err2 != nil || from < 0 || to > 26 || from > to {
+			return false
+		}
+		for count := from; count <= to; count++ {
+			counts[count] = true
+		}
+	}
+	return true
+}
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"math/rand"
+	"os"
+	"path/filepath"
 	"testing"
 
+	. "github.com/smartystreets/goconvey/convey"
+
 	"github.com/makyo/gogol/abrash"
 	"github.com/makyo/gogol/abrash1d"
 	"github.com/makyo/gogol/abrashchangelist"
 	"github.com/makyo/gogol/abrashstruct"
+	"github.com/makyo/gogol/base"
 	"github.com/makyo/gogol/naive1d"
 	"github.com/makyo/gogol/naive2d"
 	"github.com/makyo/gogol/prestafford1"
+	"github.com/makyo/gogol/render"
 	"github.com/makyo/gogol/rle"
 	"github.com/makyo/gogol/scholes"
 )
@@ -31,6 +38,110 @@ bo5b$3bo3b$2o2b3o!`)
 	return f
 }
 
+func TestEngines(t *testing.T) {
+	Convey("Given a random field which wraps around the edges", t, func() {
+		rand.Seed(1)
+		soup := rle.New(20, 18)
+		for _, row := range soup.Field {
+			for x := range row {
+				row[x] = rand.Intn(3) == 0
+			}
+		}
+
+		for name, m := range map[string]base.Model{
+			"abrash1d":         abrash1d.New(20, 18),
+			"abrashchangelist": abrashchangelist.New(20, 18),
+			"prestafford1":     prestafford1.New(20, 18),
+		} {
+			Convey(name+" should match abrash on a torus, even with cells toggled along the way", func() {
+				expected := abrash.New(20, 18)
+				expected.Ingest(soup)
+				m.Ingest(soup)
+				for gen := 1; gen <= 100; gen++ {
+					expected.Next()
+					m.Next()
+					if gen%10 == 0 {
+						for _, x := range []int{0, 7, 19} {
+							expected.ToggleCell(x, gen%18)
+							m.ToggleCell(x, gen%18)
+						}
+					}
+					So(m.Export().Field, ShouldResemble, expected.Export().Field)
+				}
+			})
+		}
+	})
+}
+
+func TestStartField(t *testing.T) {
+	Convey("Given a pattern with a rule the algorithm can't follow", t, func() {
+		pattern = acorn()
+		pattern.SetRule("B3/S23")
+		defer func() { pattern = nil }()
+		m, err := newModel("ruleloader", 20, 20, defaultAlgoOptions)
+		So(err, ShouldBeNil)
+
+		Convey("Starting the field should say so", func() {
+			So(startField(m), ShouldNotBeNil)
+		})
+
+		Convey("Unless the rule flag overrides it", func() {
+			*ruleFlag = "Life"
+			defer func() { *ruleFlag = "" }()
+			So(applyRule(m, *ruleFlag), ShouldBeNil)
+			So(startField(m), ShouldBeNil)
+			So(m.Export().Rule, ShouldEqual, "Life")
+		})
+	})
+}
+
+func TestStartField3(t *testing.T) {
+	Convey("Three-dimensional patterns should only start algorithms which can hold them", t, func() {
+		f, err := rle.Unmarshal3("3D version=1\nx=2 y=2 z=1 rule=5766\n2o$2o!")
+		So(err, ShouldBeNil)
+		pattern3 = f
+		defer func() { pattern3 = nil }()
+
+		m, err := newModel("abrash", 10, 10, defaultAlgoOptions)
+		So(err, ShouldBeNil)
+		So(startField(m), ShouldNotBeNil)
+
+		m, err = newModel("life3d", 10, 10, defaultAlgoOptions)
+		So(err, ShouldBeNil)
+		So(startField(m), ShouldBeNil)
+		So(m.Export().Rule, ShouldEqual, "3D5,6,7/6")
+	})
+}
+
+func TestLoadPattern(t *testing.T) {
+	Convey("Given an image of a pattern", t, func() {
+		path := filepath.Join(t.TempDir(), "acorn.png")
+		file, err := os.Create(path)
+		So(err, ShouldBeNil)
+		So(render.EncodePNG(file, acorn(), render.DefaultOptions()), ShouldBeNil)
+		So(file.Close(), ShouldBeNil)
+
+		Convey("Its layout should be detected", func() {
+			f, err := loadPattern(path, render.ImportOptions{})
+			So(err, ShouldBeNil)
+			So(f.Field, ShouldResemble, acorn().Field)
+		})
+
+		Convey("Or taken from the options", func() {
+			f, err := loadPattern(path, render.ImportOptions{CellSize: 8, GridWidth: 1, Left: 1, Top: 1})
+			So(err, ShouldBeNil)
+			So(f.Field, ShouldResemble, acorn().Field)
+		})
+
+		Convey("Its light cells should be dead if the options say so", func() {
+			f, err := loadPattern(path, render.ImportOptions{Polarity: render.DarkAlive})
+			So(err, ShouldBeNil)
+			So(f.Field[0][0], ShouldBeTrue)
+			So(f.Field[0][1], ShouldBeFalse)
+		})
+	})
+}
+
 func BenchmarkEvolveNaive2d(b *testing.B) {
 	m := naive2d.New(256, 256)
 	m.Ingest(acorn())
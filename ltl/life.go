@@ -0,0 +1,276 @@
+package ltl
+
+import (
+	"image/color"
+	"math/rand"
+
+	"github.com/makyo/gogol/render"
+	"github.com/makyo/gogol/rle"
+)
+
+// With neighborhoods dozens of cells across, counting neighbors one at a time for every cell would be far too slow.
+// Instead, the living cells are summed up once per generation into tables from which the count for any neighborhood
+// can be read with a handful of lookups.
+//
+// Since every cell is worked out anew each generation, rules with B0 need nothing special: the background really does
+// come alive, and with more than two states decays along with everything else, so it can't be stored as off the way
+// the isotropic engine strobes it.
+
+const (
+	dead  = 0
+	alive = 1
+)
+
+type model struct {
+	width  int
+	height int
+	field  []byte
+	rule   *Rule
+}
+
+// wrap wraps a coordinate onto a torus of the given size, even when it is more than one size away.
+func wrap(pos, size int) int {
+	return ((pos % size) + size) % size
+}
+
+// mooreCounts returns the number of living cells in the square neighborhood of every cell.
+//
+// It uses a summed-area table over the field padded on each side by the radius (wrapping around the edges), where
+// each entry holds the number of living cells above and to the left of it. The count for any rectangle is then the
+// bottom-right entry, less the entries to the left of and above it, plus the top-left entry, which was taken away twice.
+func (m *model) mooreCounts() []int32 {
+	r := m.rule.radius
+	stride := m.width + 2*r + 1
+	table := make([]int32, stride*(m.height+2*r+1))
+	for ey := 0; ey < m.height+2*r; ey++ {
+		row := wrap(ey-r, m.height) * m.width
+		var rowSum int32
+		for ex := 0; ex < m.width+2*r; ex++ {
+			if m.field[row+wrap(ex-r, m.width)] == alive {
+				rowSum++
+			}
+			table[(ey+1)*stride+ex+1] = table[ey*stride+ex+1] + rowSum
+		}
+	}
+
+	counts := make([]int32, len(m.field))
+	size := 2*r + 1
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			counts[y*m.width+x] = table[(y+size)*stride+x+size] - table[y*stride+x+size] - table[(y+size)*stride+x] + table[y*stride+x]
+		}
+	}
+	return counts
+}
+
+// vonNeumannCounts returns the number of living cells in the diamond neighborhood of every cell.
+//
+// A diamond can't be read from a summed-area table in one go, so this keeps running sums along each row instead. Each
+// row of the diamond is then a single subtraction, for 2r+1 lookups per cell.
+func (m *model) vonNeumannCounts() []int32 {
+	r := m.rule.radius
+	stride := m.width + 2*r + 1
+	sums := make([]int32, stride*m.height)
+	for y := 0; y < m.height; y++ {
+		for ex := 0; ex < m.width+2*r; ex++ {
+			var c int32
+			if m.field[y*m.width+wrap(ex-r, m.width)] == alive {
+				c = 1
+			}
+			sums[y*stride+ex+1] = sums[y*stride+ex] + c
+		}
+	}
+
+	counts := make([]int32, len(m.field))
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			var count int32
+			for dy := -r; dy <= r; dy++ {
+				reach := r - dy
+				if dy < 0 {
+					reach = r + dy
+				}
+				row := wrap(y+dy, m.height) * stride
+				count += sums[row+x+r+reach+1] - sums[row+x+r-reach]
+			}
+			counts[y*m.width+x] = count
+		}
+	}
+	return counts
+}
+
+// count returns the number of living cells in the neighborhood of the given cell, including the cell itself, by
+// visiting each of them. This is much slower than reading the tables, but only needs to be done for the one cell.
+func (m *model) count(x, y int) int {
+	r := m.rule.radius
+	count := 0
+	for dy := -r; dy <= r; dy++ {
+		reach := r
+		if m.rule.neighborhood == VonNeumann {
+			reach = r - dy
+			if dy < 0 {
+				reach = r + dy
+			}
+		}
+		row := wrap(y+dy, m.height) * m.width
+		for dx := -reach; dx <= reach; dx++ {
+			if m.field[row+wrap(x+dx, m.width)] == alive {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// next returns the next state of a cell, given its state and the number of living cells in its neighborhood,
+// including itself.
+func (m *model) next(state byte, count int) byte {
+	// The counts include the cell itself, which only some rules want.
+	if state == alive && !m.rule.middle {
+		count--
+	}
+
+	switch state {
+	case dead:
+		if count >= m.rule.bornMin && count <= m.rule.bornMax {
+			return alive
+		}
+		return dead
+	case alive:
+		if count >= m.rule.surviveMin && count <= m.rule.surviveMax {
+			return alive
+		}
+		return byte(2 % m.rule.states)
+	default:
+		return byte((int(state) + 1) % m.rule.states)
+	}
+}
+
+// Next evolves the field one generation.
+func (m *model) Next() {
+	var counts []int32
+	if m.rule.neighborhood == VonNeumann {
+		counts = m.vonNeumannCounts()
+	} else {
+		counts = m.mooreCounts()
+	}
+
+	next := make([]byte, len(m.field))
+	for i, state := range m.field {
+		next[i] = m.next(state, int(counts[i]))
+	}
+	m.field = next
+}
+
+// Size returns the width and height of the field.
+func (m *model) Size() (int, int) {
+	return m.width, m.height
+}
+
+// State returns the state of the given cell.
+func (m *model) State(x, y int) int {
+	return int(m.field[y*m.width+x])
+}
+
+// SetState sets the state of the given cell.
+func (m *model) SetState(x, y, state int) {
+	m.field[y*m.width+x] = byte(state)
+}
+
+// NextState returns the state the given cell would have after the next generation, given its neighbors as they stand.
+func (m *model) NextState(x, y int) int {
+	return int(m.next(m.field[y*m.width+x], m.count(x, y)))
+}
+
+// Populate generates a random field of automata, where each cell has a 1 in 2 chance of being alive, since most Larger
+// than Life rules need a much denser soup than Life to get going.
+func (m *model) Populate() {
+	for i, _ := range m.field {
+		m.field[i] = dead
+		if rand.Intn(2) == 0 {
+			m.field[i] = alive
+		}
+	}
+}
+
+// SetRule sets the rule the model follows from a rulestring such as R5,C0,M1,S34..58,B34..45,NM. Any cells in states
+// which the new rule doesn't have are killed.
+func (m *model) SetRule(rulestring string) error {
+	r, err := ParseRule(rulestring)
+	if err != nil {
+		return err
+	}
+	m.rule = r
+	for i, c := range m.field {
+		if int(c) >= r.states {
+			m.field[i] = dead
+		}
+	}
+	return nil
+}
+
+// Ingest sets the field to the given value.
+func (m *model) Ingest(f *rle.RLEField) {
+	startX := (m.width - f.Width) / 2
+	startY := (m.height - f.Height) / 2
+	for y, row := range f.Field {
+		for x, _ := range row {
+			state := f.State(x, y)
+			if state == dead || state >= m.rule.states {
+				continue
+			}
+			m.field[wrap(y+startY, m.height)*m.width+wrap(x+startX, m.width)] = byte(state)
+		}
+	}
+}
+
+// ToggleCell toggles whether the given cell is alive or dead.
+func (m *model) ToggleCell(x, y int) {
+	pos := y*m.width + x
+	if m.field[pos] == dead {
+		m.field[pos] = alive
+	} else {
+		m.field[pos] = dead
+	}
+}
+
+// Export returns the current state of the field.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(m.rule.String())
+	for i, c := range m.field {
+		f.SetState(i%m.width, i/m.width, int(c))
+	}
+	return f
+}
+
+// Colors used for drawing decaying cells, which fade from blue to dark blue.
+var (
+	dyingColor  = color.RGBA{0x40, 0x90, 0xff, 0xff}
+	fadingColor = color.RGBA{0x00, 0x10, 0x50, 0xff}
+)
+
+// stateColor returns the color used to draw the given state, or nil for living cells, which aren't colored.
+func (m *model) stateColor(state byte) color.Color {
+	if state == alive {
+		return nil
+	}
+	return render.Decay(dyingColor, fadingColor, state, m.rule.states)
+}
+
+// String builds the entire screen's worth of cells to be printed by returning a • for a living cell or a space for a
+// dead cell. Decaying cells, in rules with more than two states, are colored by how far along they are.
+func (m *model) String() string {
+	return render.ColorRuns(m.field, m.width, m.stateColor)
+}
+
+// New creates a model of the given size following Bosco's Rule (R5,C0,M1,S34..58,B34..45,NM).
+func New(width, height int) *model {
+	r, _ := ParseRule("R5,C0,M1,S34..58,B34..45,NM")
+	return &model{
+		width:  width,
+		height: height,
+		field:  make([]byte, width*height),
+		rule:   r,
+	}
+}
@@ -0,0 +1,130 @@
+package ltl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Neighborhood shapes.
+const (
+	// Moore neighborhoods are squares with sides of 2r+1.
+	Moore = 'M'
+
+	// Von Neumann neighborhoods are diamonds of cells within a Manhattan distance of r.
+	VonNeumann = 'N'
+)
+
+// Rule is a Larger than Life rule (see: https://conwaylife.com/wiki/Larger_than_Life ).
+type Rule struct {
+	name string
+
+	// radius is the range of the neighborhood.
+	radius int
+
+	// states is the total number of states, including dead and alive. Cells with more than two states decay as in
+	// Generations rules.
+	states int
+
+	// middle is whether a cell counts itself as a neighbor.
+	middle bool
+
+	// The minimum and maximum number of living neighbors for a cell to survive or be born.
+	surviveMin, surviveMax int
+	bornMin, bornMax       int
+
+	neighborhood byte
+}
+
+// String returns the rulestring the rule was parsed from.
+func (r *Rule) String() string {
+	return r.name
+}
+
+// ParseRule parses a Larger than Life rulestring in Golly's notation, such as R5,C0,M1,S34..58,B34..45,NM (Bosco's
+// Rule). Rules may include births with no neighbors, in which case the whole field comes alive at once.
+func ParseRule(rulestring string) (*Rule, error) {
+	r := &Rule{name: strings.TrimSpace(rulestring), neighborhood: Moore}
+	malformed := fmt.Errorf("Malformed rule - must take the form 'R#,C#,M#,S#..#,B#..#,N#': %q", rulestring)
+
+	seen := map[byte]bool{}
+	for _, part := range strings.Split(strings.ToUpper(r.name), ",") {
+		if len(part) < 2 {
+			return nil, malformed
+		}
+		key, value := part[0], part[1:]
+		if seen[key] {
+			return nil, malformed
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case 'R':
+			r.radius, err = strconv.Atoi(value)
+			if err != nil || r.radius < 1 || r.radius > 500 {
+				return nil, malformed
+			}
+		case 'C':
+			r.states, err = strconv.Atoi(value)
+			if err != nil || r.states < 0 || r.states > 256 {
+				return nil, malformed
+			}
+		case 'M':
+			if value != "0" && value != "1" {
+				return nil, malformed
+			}
+			r.middle = value == "1"
+		case 'S':
+			r.surviveMin, r.surviveMax, err = parseRange(value)
+			if err != nil {
+				return nil, malformed
+			}
+		case 'B':
+			r.bornMin, r.bornMax, err = parseRange(value)
+			if err != nil {
+				return nil, malformed
+			}
+		case 'N':
+			if value != "M" && value != "N" {
+				return nil, malformed
+			}
+			r.neighborhood = value[0]
+		default:
+			return nil, malformed
+		}
+	}
+
+	// Everything but the neighborhood is required.
+	for _, key := range []byte("RCMSB") {
+		if !seen[key] {
+			return nil, malformed
+		}
+	}
+
+	// Zero states is the same as two.
+	if r.states < 2 {
+		r.states = 2
+	}
+	return r, nil
+}
+
+// parseRange parses a range of counts in the form min..max.
+func parseRange(value string) (int, int, error) {
+	low, high, found := strings.Cut(value, "..")
+	if !found {
+		return 0, 0, fmt.Errorf("missing '..'")
+	}
+	min, err := strconv.Atoi(low)
+	if err != nil {
+		return 0, 0, err
+	}
+	max, err := strconv.Atoi(high)
+	if err != nil {
+		return 0, 0, err
+	}
+	if min < 0 || max < min {
+		return 0, 0, fmt.Errorf("bad range")
+	}
+	return min, max, nil
+}
@@ -0,0 +1,178 @@
+package ltl
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/rle"
+)
+
+func TestParseRule(t *testing.T) {
+	Convey("When parsing Bosco's Rule", t, func() {
+		r, err := ParseRule("R5,C0,M1,S34..58,B34..45,NM")
+		So(err, ShouldBeNil)
+
+		Convey("It should set every part of the rule", func() {
+			So(r.radius, ShouldEqual, 5)
+			So(r.states, ShouldEqual, 2)
+			So(r.middle, ShouldBeTrue)
+			So([]int{r.surviveMin, r.surviveMax, r.bornMin, r.bornMax}, ShouldResemble, []int{34, 58, 34, 45})
+			So(r.neighborhood, ShouldEqual, Moore)
+		})
+	})
+
+	Convey("Rules may include births with no neighbors", t, func() {
+		r, err := ParseRule("R5,C0,M1,S34..58,B0..45,NM")
+		So(err, ShouldBeNil)
+		So(r.bornMin, ShouldEqual, 0)
+	})
+
+	Convey("Malformed rules should return errors", t, func() {
+		for _, rule := range []string{"", "B3/S23", "R5,C0,M1,S34..58", "R0,C0,M1,S34..58,B34..45,NM", "R5,C0,M2,S34..58,B34..45,NM", "R5,C0,M1,S58..34,B34..45,NM", "R5,C0,M1,S34..58,B34..45,NX"} {
+			_, err := ParseRule(rule)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestCounts(t *testing.T) {
+	Convey("Given a random field", t, func() {
+		m := New(23, 17)
+		rand.Seed(1)
+		m.Populate()
+
+		// Count the neighbors the slow way to check against.
+		bruteForce := func(inside func(dx, dy int) bool) []int32 {
+			r := m.rule.radius
+			counts := make([]int32, len(m.field))
+			for y := 0; y < m.height; y++ {
+				for x := 0; x < m.width; x++ {
+					for dy := -r; dy <= r; dy++ {
+						for dx := -r; dx <= r; dx++ {
+							if inside(dx, dy) && m.field[wrap(y+dy, m.height)*m.width+wrap(x+dx, m.width)] == alive {
+								counts[y*m.width+x]++
+							}
+						}
+					}
+				}
+			}
+			return counts
+		}
+
+		Convey("Moore counts should match counting each cell, even when the radius is larger than the field", func() {
+			for _, radius := range []int{1, 5, 20} {
+				m.rule.radius = radius
+				So(m.mooreCounts(), ShouldResemble, bruteForce(func(dx, dy int) bool { return true }))
+			}
+		})
+
+		Convey("Von Neumann counts should match counting each cell", func() {
+			for _, radius := range []int{1, 5, 20} {
+				m.rule.radius = radius
+				So(m.vonNeumannCounts(), ShouldResemble, bruteForce(func(dx, dy int) bool {
+					if dx < 0 {
+						dx = -dx
+					}
+					if dy < 0 {
+						dy = -dy
+					}
+					return dx+dy <= radius
+				}))
+			}
+		})
+	})
+}
+
+func TestNext(t *testing.T) {
+	Convey("Given a glider following Life written as a Larger than Life rule", t, func() {
+		f, err := rle.Unmarshal(`x = 3, y = 3, rule = R1,C0,M0,S2..3,B3..3,NM
+bo$2bo$3o!`)
+		So(err, ShouldBeNil)
+		m := New(10, 10)
+		So(m.SetRule(f.Rule), ShouldBeNil)
+		m.Ingest(f)
+		start := m.Export()
+
+		Convey("It should move one cell diagonally every four generations", func() {
+			for i := 0; i < 4; i++ {
+				m.Next()
+			}
+			So(m.Export().Field, ShouldResemble, start.Crop(-1, -1, 10, 10).Field)
+		})
+	})
+}
+
+func TestBirthsWithNoNeighbors(t *testing.T) {
+	Convey("Given an empty field following a rule with B0", t, func() {
+		m := New(12, 10)
+		So(m.SetRule("R2,C3,M0,S3..8,B0..2,NM"), ShouldBeNil)
+
+		Convey("The whole field should come alive, then decay, then die", func() {
+			for _, state := range []int{alive, 2, dead, alive} {
+				m.Next()
+				for _, c := range m.field {
+					So(c, ShouldEqual, state)
+				}
+			}
+		})
+	})
+
+	Convey("Given a random field following rules with B0", t, func() {
+		rand.Seed(1)
+		for _, rule := range []string{"R2,C0,M0,S3..8,B0..2,NM", "R1,C4,M1,S2..4,B0..1,NN"} {
+			m := New(15, 13)
+			So(m.SetRule(rule), ShouldBeNil)
+			m.Populate()
+
+			Convey("It should match the rule applied directly to every cell under "+rule, func() {
+				for i := 0; i < 6; i++ {
+					expected := applyRule(m)
+					m.Next()
+					So(m.field, ShouldResemble, expected)
+				}
+			})
+		}
+	})
+}
+
+// applyRule works out the next generation of a model's field one cell and one neighbor at a time.
+func applyRule(m *model) []byte {
+	r := m.rule
+	next := make([]byte, len(m.field))
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			count := 0
+			for dy := -r.radius; dy <= r.radius; dy++ {
+				for dx := -r.radius; dx <= r.radius; dx++ {
+					if r.neighborhood == VonNeumann && abs(dx)+abs(dy) > r.radius || dx == 0 && dy == 0 && !r.middle {
+						continue
+					}
+					if m.field[wrap(y+dy, m.height)*m.width+wrap(x+dx, m.width)] == alive {
+						count++
+					}
+				}
+			}
+			state := m.field[y*m.width+x]
+			switch {
+			case state == dead && count >= r.bornMin && count <= r.bornMax:
+				next[y*m.width+x] = alive
+			case state == alive && count >= r.surviveMin && count <= r.surviveMax:
+				next[y*m.width+x] = alive
+			case state == alive:
+				next[y*m.width+x] = byte(2 % r.states)
+			case state > alive:
+				next[y*m.width+x] = byte((int(state) + 1) % r.states)
+			}
+		}
+	}
+	return next
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
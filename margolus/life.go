@@ -0,0 +1,156 @@
+package margolus
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/makyo/gogol/rle"
+)
+
+// Block cellular automata don't look at neighbors at all. Instead, the field is split into 2x2 blocks, each of which is
+// replaced all at once according to the rule. On even generations, the blocks start at the top left corner, and on odd
+// generations, they're shifted down and to the right by one cell, so that information can cross between blocks. This
+// is the Margolus neighborhood (see: https://conwaylife.com/wiki/Block_cellular_automaton ).
+//
+// For the blocks to line up as they wrap around the edges, the field must be an even number of cells in each direction.
+
+type model struct {
+	width      int
+	height     int
+	field      []byte
+	rule       *Rule
+	generation int
+	backwards  bool
+}
+
+// step replaces every block in the partition used after the given generation using the given table.
+func (m *model) step(generation int, table *[16]byte) {
+	offset := (generation%2 + 2) % 2
+	for y := offset; y < m.height+offset; y += 2 {
+		top := y * m.width
+		bottom := ((y + 1) % m.height) * m.width
+		for x := offset; x < m.width+offset; x += 2 {
+			right := (x + 1) % m.width
+			block := m.field[top+x] | m.field[top+right]<<1 | m.field[bottom+x]<<2 | m.field[bottom+right]<<3
+			next := table[block]
+			m.field[top+x] = next & topLeft
+			m.field[top+right] = next & topRight >> 1
+			m.field[bottom+x] = next & bottomLeft >> 2
+			m.field[bottom+right] = next & bottomRight >> 3
+		}
+	}
+}
+
+// Next evolves the field one generation, or, when running backwards, undoes the last generation.
+func (m *model) Next() {
+	if m.backwards {
+		m.generation--
+		m.step(m.generation, &m.rule.inverse)
+		return
+	}
+	m.step(m.generation, &m.rule.table)
+	m.generation++
+}
+
+// Reverse switches the direction in which the model runs. Only reversible rules can be run backwards.
+func (m *model) Reverse() error {
+	if !m.rule.reversible {
+		return fmt.Errorf("The rule %s is not reversible", m.rule)
+	}
+	m.backwards = !m.backwards
+	return nil
+}
+
+// Populate generates a random field of automata, where each cell has a 1 in 5 chance of being alive.
+func (m *model) Populate() {
+	m.generation = 0
+	for i, _ := range m.field {
+		m.field[i] = 0
+		if rand.Intn(5) == 0 {
+			m.field[i] = 1
+		}
+	}
+}
+
+// SetRule sets the rule the model follows from a rulestring such as Critters. If the new rule isn't reversible, the
+// model runs forwards.
+func (m *model) SetRule(rulestring string) error {
+	r, err := ParseRule(rulestring)
+	if err != nil {
+		return err
+	}
+	m.rule = r
+	if !r.reversible {
+		m.backwards = false
+	}
+	return nil
+}
+
+// Ingest sets the field to the given value. The pattern is
+// placed so that it lines up with the blocks the same way it did in its own field, and the model starts again from
+// the first generation, running forwards, with nothing left of the field it had before.
+func (m *model) Ingest(f *rle.RLEField) {
+	m.generation = 0
+	m.backwards = false
+	for i := range m.field {
+		m.field[i] = 0
+	}
+	startX := (m.width - f.Width) / 2 &^ 1
+	startY := (m.height - f.Height) / 2 &^ 1
+	for y, row := range f.Field {
+		for x, col := range row {
+			if col {
+				m.field[((y+startY)%m.height+m.height)%m.height*m.width+((x+startX)%m.width+m.width)%m.width] = 1
+			}
+		}
+	}
+}
+
+// ToggleCell toggles whether the given cell is alive or dead.
+func (m *model) ToggleCell(x, y int) {
+	if x >= m.width || y >= m.height {
+		return
+	}
+	m.field[y*m.width+x] ^= 1
+}
+
+// Export returns the current state of the field.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(m.rule.String())
+	for i, c := range m.field {
+		f.Field[i/m.width][i%m.width] = c == 1
+	}
+	return f
+}
+
+// String builds the entire screen's worth of cells to be printed by returning a • for a living cell or a space for a dead cell.
+func (m *model) String() string {
+	var frame strings.Builder
+	for i, c := range m.field {
+		if i > 0 && i%m.width == 0 {
+			frame.WriteString("\n")
+		}
+		if c == 1 {
+			frame.WriteString("•")
+		} else {
+			frame.WriteString(" ")
+		}
+	}
+	return frame.String()
+}
+
+// New creates a model following the Billiard Ball Machine rule. Odd sizes are rounded down to the nearest even number
+// of cells so that the blocks line up.
+func New(width, height int) *model {
+	r, _ := ParseRule("BBM")
+	width &^= 1
+	height &^= 1
+	return &model{
+		width:  width,
+		height: height,
+		field:  make([]byte, width*height),
+		rule:   r,
+	}
+}
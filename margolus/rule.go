@@ -0,0 +1,95 @@
+package margolus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Each 2x2 block is numbered by adding up the cells in it which are alive, as in MCell:
+//
+//	1 2
+//	4 8
+const (
+	topLeft     = 1
+	topRight    = 2
+	bottomLeft  = 4
+	bottomRight = 8
+)
+
+// Rule is a block rule, giving the next state of each of the sixteen possible blocks.
+type Rule struct {
+	name  string
+	table [16]byte
+
+	// A rule is reversible if no two blocks become the same block, in which case inverse undoes table.
+	reversible bool
+	inverse    [16]byte
+}
+
+// String returns the rulestring the rule was parsed from.
+func (r *Rule) String() string {
+	return r.name
+}
+
+// Reversible returns whether the rule can be run backwards.
+func (r *Rule) Reversible() bool {
+	return r.reversible
+}
+
+// Well-known block rules, by the names used for them in Golly and elsewhere.
+var named = map[string]string{
+	// The Billiard Ball Machine, in which balls travel diagonally and bounce off one another.
+	"bbm":                   "M0,8,4,3,2,5,9,7,1,6,10,11,12,13,14,15",
+	"bbm-margolus-emulated": "M0,8,4,3,2,5,9,7,1,6,10,11,12,13,14,15",
+
+	// Critters, which complements every block except those with two cells alive, and also turns those with three
+	// cells alive around.
+	"critters":                  "M15,14,13,3,11,5,6,1,7,9,10,2,12,4,8,0",
+	"crittersmargolus_emulated": "M15,14,13,3,11,5,6,1,7,9,10,2,12,4,8,0",
+
+	// Tron, which complements blocks which are entirely alive or entirely dead.
+	"tron":                  "M15,1,2,3,4,5,6,7,8,9,10,11,12,13,14,0",
+	"tronmargolus_emulated": "M15,1,2,3,4,5,6,7,8,9,10,11,12,13,14,0",
+}
+
+// ParseRule parses a block rule, either by name (BBM, Critters, or Tron), or as a table of the next state of each block
+// in order, such as M0,8,4,3,2,5,9,7,1,6,10,11,12,13,14,15. MCell's form, with MS,D in place of M and semicolons in
+// place of commas, is also accepted.
+func ParseRule(rulestring string) (*Rule, error) {
+	r := &Rule{name: strings.TrimSpace(rulestring)}
+	malformed := fmt.Errorf("Malformed rule - must be BBM, Critters, Tron, or take the form 'M#,#,...' with 16 blocks from 0 to 15: %q", rulestring)
+
+	spec := strings.ToUpper(r.name)
+	if table, ok := named[strings.ToLower(r.name)]; ok {
+		spec = table
+	}
+	switch {
+	case strings.HasPrefix(spec, "MS,D"):
+		spec = strings.ReplaceAll(spec[4:], ";", ",")
+	case strings.HasPrefix(spec, "M"):
+		spec = spec[1:]
+	default:
+		return nil, malformed
+	}
+
+	blocks := strings.Split(spec, ",")
+	if len(blocks) != 16 {
+		return nil, malformed
+	}
+	seen := [16]bool{}
+	r.reversible = true
+	for i, block := range blocks {
+		next, err := strconv.Atoi(strings.TrimSpace(block))
+		if err != nil || next < 0 || next > 15 {
+			return nil, malformed
+		}
+		r.table[i] = byte(next)
+		if seen[next] {
+			r.reversible = false
+		}
+		seen[next] = true
+		r.inverse[next] = byte(i)
+	}
+	return r, nil
+}
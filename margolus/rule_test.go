@@ -0,0 +1,112 @@
+package margolus
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseRule(t *testing.T) {
+	Convey("Named rules should match their tables", t, func() {
+		for name, table := range map[string]string{
+			"BBM":      "M0,8,4,3,2,5,9,7,1,6,10,11,12,13,14,15",
+			"critters": "MS,D15;14;13;3;11;5;6;1;7;9;10;2;12;4;8;0",
+			"Tron":     "M15,1,2,3,4,5,6,7,8,9,10,11,12,13,14,0",
+		} {
+			a, err := ParseRule(name)
+			So(err, ShouldBeNil)
+			b, err := ParseRule(table)
+			So(err, ShouldBeNil)
+			So(a.table, ShouldEqual, b.table)
+			So(a.Reversible(), ShouldBeTrue)
+		}
+	})
+
+	Convey("Rules where two blocks become the same block aren't reversible", t, func() {
+		r, err := ParseRule("M0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0")
+		So(err, ShouldBeNil)
+		So(r.Reversible(), ShouldBeFalse)
+	})
+
+	Convey("Malformed rules should return errors", t, func() {
+		for _, rule := range []string{"", "B3/S23", "M0,1,2", "M0,8,4,3,2,5,9,7,1,6,10,11,12,13,14,16", "MS,D0;8;4"} {
+			_, err := ParseRule(rule)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestNext(t *testing.T) {
+	Convey("Under the Billiard Ball Machine, a lone ball should travel diagonally, wrapping around the edges", t, func() {
+		m := New(8, 8)
+		m.ToggleCell(0, 0)
+		m.Next()
+		So(m.field[1*8+1], ShouldEqual, 1)
+		m.Next()
+		So(m.field[2*8+2], ShouldEqual, 1)
+		for i := 0; i < 6; i++ {
+			m.Next()
+		}
+		So(m.field[0], ShouldEqual, 1)
+	})
+
+	Convey("Odd sizes should be rounded down so that the blocks line up", t, func() {
+		m := New(9, 7)
+		So(m.width, ShouldEqual, 8)
+		So(m.height, ShouldEqual, 6)
+		m.ToggleCell(8, 6)
+	})
+
+	Convey("Given a random field following Critters", t, func() {
+		m := New(32, 32)
+		m.SetRule("Critters")
+		rand.Seed(1)
+		m.Populate()
+		start := m.Export()
+
+		Convey("Running it backwards should bring back where it started", func() {
+			for i := 0; i < 51; i++ {
+				m.Next()
+			}
+			So(m.Export().Field, ShouldNotResemble, start.Field)
+			So(m.Reverse(), ShouldBeNil)
+			for i := 0; i < 51; i++ {
+				m.Next()
+			}
+			So(m.Export().Field, ShouldResemble, start.Field)
+
+			Convey("And then past where it started", func() {
+				m.Next()
+				m.Reverse()
+				m.Next()
+				So(m.Export().Field, ShouldResemble, start.Field)
+			})
+		})
+	})
+
+	Convey("Ingesting a pattern should start again from the first generation, running forwards", t, func() {
+		a, b := New(16, 16), New(16, 16)
+		a.SetRule("Critters")
+		b.SetRule("Critters")
+		rand.Seed(2)
+		a.Populate()
+		start := a.Export()
+		b.Next()
+		b.Reverse()
+		b.Ingest(start)
+		So(b.generation, ShouldEqual, 0)
+		So(b.backwards, ShouldBeFalse)
+		for i := 0; i < 5; i++ {
+			a.Next()
+			b.Next()
+		}
+		So(b.Export().Field, ShouldResemble, a.Export().Field)
+	})
+
+	Convey("Rules which aren't reversible can't be run backwards", t, func() {
+		m := New(4, 4)
+		m.SetRule("M0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0")
+		So(m.Reverse(), ShouldNotBeNil)
+	})
+}
@@ -91,6 +91,15 @@ func (m *model) ToggleCell(x, y int) {
 	}
 }
 
+// Export returns the current state of the field.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	for i, cell := range m.field {
+		f.Field[i/m.width][i%m.width] = cell == 1
+	}
+	return f
+}
+
 // View builds the entire screen's worth of cells to be printed by returning a • for a living cell or a space for a dead cell.
 func (m *model) String() string {
 	var frame string
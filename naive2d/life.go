@@ -31,41 +31,7 @@ func (m *model) Next() {
 
 		// Loop over columns...
 		for x, _ := range m.field[y] {
-			neighborCount := 0
-
-			// Count the adjacent living cells on the row above.
-			neighborCount += m.wrapPos(x-1, y-1)
-			neighborCount += m.wrapPos(x, y-1)
-			neighborCount += m.wrapPos(x+1, y-1)
-
-			// Count the adjacent cells to either side.
-			neighborCount += m.wrapPos(x-1, y)
-			neighborCount += m.wrapPos(x+1, y)
-
-			// Count the adjacent cells on the row below.
-			neighborCount += m.wrapPos(x-1, y+1)
-			neighborCount += m.wrapPos(x, y+1)
-			neighborCount += m.wrapPos(x+1, y+1)
-
-			// Evolve the current cell by the following rules:
-			//
-			// 1. A dead cell becomes live if it's surrounded by exactly three living cells to represent breeding.
-			// 2. A living cell dies of loneliness if it has 0 or 1 neighbors.
-			// 3. A living cell dies of overcrowding if it has more than 3 neighbors.
-			// 4. A living cell stays alive if it has 2 or 3 neighbors.
-			if m.field[y][x] == 0 {
-				if neighborCount == 3 {
-					next[y][x] = 1
-				} else {
-					next[y][x] = 0
-				}
-			} else if m.field[y][x] == 1 {
-				if neighborCount < 2 || neighborCount > 3 {
-					next[y][x] = 0
-				} else {
-					next[y][x] = 1
-				}
-			}
+			next[y][x] = m.nextState(x, y)
 		}
 	}
 	for y, row := range next {
@@ -75,6 +41,62 @@ func (m *model) Next() {
 	}
 }
 
+// nextState returns the state the given cell will have in the next generation.
+func (m *model) nextState(x, y int) int {
+	neighborCount := 0
+
+	// Count the adjacent living cells on the row above.
+	neighborCount += m.wrapPos(x-1, y-1)
+	neighborCount += m.wrapPos(x, y-1)
+	neighborCount += m.wrapPos(x+1, y-1)
+
+	// Count the adjacent cells to either side.
+	neighborCount += m.wrapPos(x-1, y)
+	neighborCount += m.wrapPos(x+1, y)
+
+	// Count the adjacent cells on the row below.
+	neighborCount += m.wrapPos(x-1, y+1)
+	neighborCount += m.wrapPos(x, y+1)
+	neighborCount += m.wrapPos(x+1, y+1)
+
+	// Evolve the current cell by the following rules:
+	//
+	// 1. A dead cell becomes live if it's surrounded by exactly three living cells to represent breeding.
+	// 2. A living cell dies of loneliness if it has 0 or 1 neighbors.
+	// 3. A living cell dies of overcrowding if it has more than 3 neighbors.
+	// 4. A living cell stays alive if it has 2 or 3 neighbors.
+	if m.field[y][x] == 0 {
+		if neighborCount == 3 {
+			return 1
+		}
+		return 0
+	}
+	if neighborCount < 2 || neighborCount > 3 {
+		return 0
+	}
+	return 1
+}
+
+// Size returns the width and height of the field.
+func (m *model) Size() (int, int) {
+	return m.width, m.height
+}
+
+// State returns 1 if the given cell is alive and 0 if it is dead.
+func (m *model) State(x, y int) int {
+	return m.field[y][x]
+}
+
+// SetState sets the given cell to alive (1) or dead (0).
+func (m *model) SetState(x, y, state int) {
+	m.field[y][x] = state
+}
+
+// NextState returns the state the given cell would have after the next generation, given its neighbors as they stand.
+func (m *model) NextState(x, y int) int {
+	return m.nextState(x, y)
+}
+
 // generateField generates a random field of automata, where each cell has a 1 in 5 chance of being alive.
 func (m *model) Populate() {
 	for y, _ := range m.field {
@@ -106,6 +128,17 @@ func (m *model) ToggleCell(x, y int) {
 	}
 }
 
+// Export returns the current state of the field.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	for y, row := range m.field {
+		for x, col := range row {
+			f.Field[y][x] = col == 1
+		}
+	}
+	return f
+}
+
 // View builds the entire screen's worth of cells to be printed by returning a • for a living cell or a space for a dead cell.
 func (m *model) String() string {
 	var frame string
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/makyo/gogol/render"
+)
+
+// pngCommand writes the state of a model after some number of generations to a PNG image, or a still GIF if the file
+// name ends in .gif.
+//
+//	gogol png [flags] [pattern.rle]
+//
+// If no pattern is given, the field is populated at random.
+func pngCommand(args []string) error {
+	fs := flag.NewFlagSet("png", flag.ExitOnError)
+	start := modelFlags(fs, 64)
+	out := fs.String("o", "out.png", "File to write the image to, as a still GIF if it ends in .gif and PNG otherwise")
+	generations := fs.Int("generations", 0, "Number of generations to run before drawing the field")
+	cellSize := fs.Int("cell", 8, "Size of each cell in pixels")
+	grid := fs.Bool("grid", true, "Draw grid lines between cells")
+	fs.Parse(args)
+
+	m, err := start()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < *generations; i++ {
+		m.Next()
+	}
+
+	opts := render.DefaultOptions()
+	opts.CellSize = *cellSize
+	opts.Grid = *grid
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(strings.ToLower(*out), ".gif") {
+		err = render.EncodeGIF(file, render.Snapshot(m), opts)
+	} else {
+		err = render.EncodePNG(file, render.Snapshot(m), opts)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -248,6 +248,18 @@ func (m *model) ToggleCell(x, y int) {
 	}
 }
 
+// Export returns the current state of the field.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	for y, row := range f.Field {
+		for x, _ := range row {
+			pos := y*m.width + x
+			row[x] = m.state(pos/3, pos%3)
+		}
+	}
+	return f
+}
+
 // View builds the entire screen's worth of cells to be printed by returning a • for a living cell or a space for a dead cell.
 func (m *model) String() string {
 	var frame string
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/makyo/gogol/stats"
+)
+
+// writeRecording writes the numbers a recorder has recorded to a file, as JSON if its name ends in .json and as CSV
+// otherwise.
+func writeRecording(path string, r *stats.Recorder) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(strings.ToLower(path), ".json") {
+		err = r.WriteJSON(file)
+	} else {
+		err = r.WriteCSV(file)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// recordCommand runs a model without the UI, recording the population, births, deaths, and bounding box of each
+// generation.
+//
+//	gogol record [flags] [pattern.rle]
+//
+// If no pattern is given, the field is populated at random.
+func recordCommand(args []string) error {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	start := modelFlags(fs, 128)
+	out := fs.String("o", "population.csv", "File to write the numbers to, as JSON if it ends in .json and CSV otherwise")
+	generations := fs.Int("generations", 1000, "Number of generations to run")
+	fs.Parse(args)
+
+	m, err := start()
+	if err != nil {
+		return err
+	}
+
+	r := stats.New()
+	r.Run(m, *generations)
+	return writeRecording(*out, r)
+}
+
+// writeHeatMap writes a heat map to a file, as CSV if its name ends in .csv and as a PNG with cells of the given size
+// otherwise.
+func writeHeatMap(path string, h *stats.HeatMap, cellSize int) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(strings.ToLower(path), ".csv") {
+		err = h.WriteCSV(file)
+	} else {
+		err = h.WritePNG(file, cellSize)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// heatMapCommand runs a model without the UI, counting the births, deaths, or generations alive of each cell, and
+// writes the counts out as a heat map.
+//
+//	gogol heatmap [flags] [pattern.rle]
+//
+// If no pattern is given, the field is populated at random.
+func heatMapCommand(args []string) error {
+	fs := flag.NewFlagSet("heatmap", flag.ExitOnError)
+	start := modelFlags(fs, 128)
+	out := fs.String("o", "heatmap.png", "File to write the heat map to, as CSV if it ends in .csv and PNG otherwise")
+	kind := fs.String("kind", stats.Alive, "What to count for each cell: births, deaths, or alive")
+	generations := fs.Int("generations", 1000, "Number of generations to run")
+	cellSize := fs.Int("cell", 4, "Size of each cell in pixels")
+	fs.Parse(args)
+
+	h, err := stats.NewHeatMap(*kind)
+	if err != nil {
+		return err
+	}
+	m, err := start()
+	if err != nil {
+		return err
+	}
+
+	h.Run(m, *generations)
+	return writeHeatMap(*out, h, *cellSize)
+}
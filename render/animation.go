@@ -0,0 +1,123 @@
+package render
+
+import (
+	"image"
+	"image/gif"
+	"io"
+
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/rle"
+)
+
+// AnimationOptions controls how a run of a model is recorded.
+type AnimationOptions struct {
+	Options
+
+	// Generations is the number of generations to run the model for.
+	Generations int
+
+	// Every records only every nth generation. Zero or one records every generation.
+	Every int
+
+	// Delay is the time between frames in hundredths of a second.
+	Delay int
+
+	// Region, in cells, is the part of the field to record. If it is empty, the whole field is recorded.
+	Region image.Rectangle
+
+	// FollowBounds crops the animation to the smallest rectangle which contains every living cell across the whole
+	// run, rather than to Region.
+	FollowBounds bool
+}
+
+// Snapshot returns the field as the model draws it: the diagram of models which draw more than their current field,
+// and the exported field of the rest.
+func Snapshot(m base.Model) *rle.RLEField {
+	if d, ok := m.(base.Diagrammed); ok {
+		return d.Diagram()
+	}
+	return m.Export()
+}
+
+// frame holds the state of the field at a recorded generation, along with the state at the generation before it so
+// that recently dead cells can be found.
+type frame struct {
+	current, previous *rle.RLEField
+}
+
+// draw draws the given region of the frame.
+func (fr frame) draw(region image.Rectangle, opts AnimationOptions) *image.Paletted {
+	current := fr.current.Crop(region.Min.X, region.Min.Y, region.Dx(), region.Dy())
+	prev := fr.previous.Crop(region.Min.X, region.Min.Y, region.Dx(), region.Dy())
+	return opts.draw(region.Dx(), region.Dy(), func(x, y int) uint8 {
+		switch {
+		case current.Field[y][x]:
+			return aliveIndex
+		case prev.Field[y][x]:
+			return recentlyDeadIndex
+		default:
+			return deadIndex
+		}
+	})
+}
+
+// Animate runs the model for the given number of generations and returns an animated GIF of the run. The state of the
+// model before the first call to Next is the first frame.
+func Animate(m base.Model, opts AnimationOptions) *gif.GIF {
+	if opts.Every < 1 {
+		opts.Every = 1
+	}
+	anim := &gif.GIF{}
+	add := func(img *image.Paletted) {
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, opts.Delay)
+	}
+
+	// Unless the animation follows the pattern, the part of the field to draw is known up front, so each frame can
+	// be drawn as soon as it's run rather than holding on to the whole field for every generation.
+	previous := Snapshot(m)
+	region := opts.Region
+	if region.Empty() {
+		region = image.Rect(0, 0, previous.Width, previous.Height)
+	}
+	var frames []frame
+	for gen := 0; gen <= opts.Generations; gen++ {
+		current := previous
+		if gen > 0 {
+			m.Next()
+			current = Snapshot(m)
+		}
+		if gen%opts.Every == 0 {
+			if opts.FollowBounds {
+				frames = append(frames, frame{current, previous})
+			} else {
+				add(frame{current, previous}.draw(region, opts))
+			}
+		}
+		previous = current
+	}
+	if !opts.FollowBounds {
+		return anim
+	}
+
+	// Otherwise, crop to everywhere the pattern went over the whole run.
+	bounds := image.Rectangle{}
+	for _, fr := range frames {
+		left, top, width, height := fr.current.BoundingBox()
+		if width > 0 {
+			bounds = bounds.Union(image.Rect(left, top, left+width, top+height))
+		}
+	}
+	if !bounds.Empty() {
+		region = bounds
+	}
+	for _, fr := range frames {
+		add(fr.draw(region, opts))
+	}
+	return anim
+}
+
+// EncodeAnimation runs the model and writes an animated GIF of the run to w.
+func EncodeAnimation(w io.Writer, m base.Model, opts AnimationOptions) error {
+	return gif.EncodeAll(w, Animate(m, opts))
+}
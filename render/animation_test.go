@@ -0,0 +1,87 @@
+package render_test
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/abrash"
+	"github.com/makyo/gogol/elementary"
+	"github.com/makyo/gogol/render"
+)
+
+func TestAnimate(t *testing.T) {
+	Convey("Given a glider on a small field", t, func() {
+		m := abrash.New(12, 12)
+		m.Ingest(glider())
+		opts := render.AnimationOptions{Options: render.DefaultOptions(), Generations: 8, Delay: 5}
+		opts.CellSize = 2
+		opts.Grid = false
+
+		Convey("Every generation, including the first, should be a frame", func() {
+			anim := render.Animate(m, opts)
+			So(anim.Image, ShouldHaveLength, 9)
+			So(anim.Delay, ShouldResemble, []int{5, 5, 5, 5, 5, 5, 5, 5, 5})
+			So(anim.Image[0].Bounds().Dx(), ShouldEqual, 24)
+		})
+
+		Convey("Every should skip generations between frames", func() {
+			opts.Every = 4
+			So(render.Animate(m, opts).Image, ShouldHaveLength, 3)
+			opts.Every = 3
+			So(render.Animate(m, opts).Image, ShouldHaveLength, 3)
+		})
+
+		Convey("FollowBounds should crop to everywhere the glider went", func() {
+			opts.FollowBounds = true
+			anim := render.Animate(m, opts)
+			So(anim.Image, ShouldHaveLength, 9)
+
+			// The glider starts in a 3x3 box and moves two cells down and to the right over eight generations.
+			So(anim.Image[0].Bounds().Dx(), ShouldEqual, 5*2)
+			So(anim.Image[0].Bounds().Dy(), ShouldEqual, 5*2)
+		})
+
+		Convey("Region should crop to part of the field", func() {
+			opts.Region.Max.X, opts.Region.Max.Y = 6, 4
+			So(render.Animate(m, opts).Image[0].Bounds().Dx(), ShouldEqual, 12)
+			So(render.Animate(m, opts).Image[0].Bounds().Dy(), ShouldEqual, 8)
+		})
+
+		Convey("Cells which have just died should be drawn in the RecentlyDead color", func() {
+			opts.RecentlyDead = color.RGBA{0x80, 0x20, 0x20, 0xff}
+			anim := render.Animate(m, opts)
+
+			// The glider's top cell, at (5, 4) on the field, dies in the first generation.
+			first, second := anim.Image[0], anim.Image[1]
+			So(first.At(5*2, 4*2), ShouldResemble, color.White)
+			So(second.At(5*2, 4*2), ShouldResemble, color.RGBA{0x80, 0x20, 0x20, 0xff})
+			So(second.ColorIndexAt(0, 0), ShouldEqual, first.ColorIndexAt(0, 0))
+		})
+
+		Convey("The animation should encode as a GIF", func() {
+			var buf bytes.Buffer
+			So(render.EncodeAnimation(&buf, m, opts), ShouldBeNil)
+			anim, err := gif.DecodeAll(&buf)
+			So(err, ShouldBeNil)
+			So(anim.Image, ShouldHaveLength, 9)
+		})
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	Convey("One-dimensional automata should be animated as their space-time diagram rather than a single row", t, func() {
+		m := elementary.New(16, 8)
+		m.PopulateSingle()
+		opts := render.AnimationOptions{Options: render.DefaultOptions(), Generations: 4}
+		opts.CellSize = 1
+		opts.Grid = false
+		anim := render.Animate(m, opts)
+		So(anim.Image[0].Bounds().Dy(), ShouldEqual, 8)
+		So(render.Snapshot(m).Height, ShouldEqual, 8)
+		So(m.Export().Height, ShouldEqual, 1)
+	})
+}
@@ -0,0 +1,132 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+
+	"github.com/makyo/gogol/rle"
+)
+
+// Options controls how a field is drawn.
+type Options struct {
+	// CellSize is the width and height of each cell in pixels.
+	CellSize int
+
+	// Grid draws a one pixel line around each cell in GridColor.
+	Grid bool
+
+	// The colors used for living cells, dead cells, and grid lines.
+	Alive, Dead, GridColor color.Color
+
+	// RecentlyDead is the color used in animations for cells which died in the previous generation. If it is nil, they
+	// are drawn as dead.
+	RecentlyDead color.Color
+}
+
+// DefaultOptions returns options which mimic Golly's default look: white cells on a black background with a dark grey grid.
+func DefaultOptions() Options {
+	return Options{
+		CellSize:  8,
+		Grid:      true,
+		Alive:     color.White,
+		Dead:      color.Black,
+		GridColor: color.Gray{0x40},
+	}
+}
+
+// Indices into the palette used by Image.
+const (
+	deadIndex = iota
+	aliveIndex
+	gridIndex
+	recentlyDeadIndex
+)
+
+// palette returns the colors to be used for the image, in the order of the indices above.
+func (o Options) palette() color.Palette {
+	recentlyDead := o.RecentlyDead
+	if recentlyDead == nil {
+		recentlyDead = o.Dead
+	}
+	return color.Palette{o.Dead, o.Alive, o.GridColor, recentlyDead}
+}
+
+// pitch returns the distance in pixels from the start of one cell to the start of the next.
+func (o Options) pitch() int {
+	if o.Grid {
+		return o.CellSize + 1
+	}
+	return o.CellSize
+}
+
+// origin returns the pixel offset of the first cell.
+func (o Options) origin() int {
+	if o.Grid {
+		return 1
+	}
+	return 0
+}
+
+// bounds returns the size of the image needed to draw a field of the given size.
+func (o Options) bounds(width, height int) image.Rectangle {
+	return image.Rect(0, 0, width*o.pitch()+o.origin(), height*o.pitch()+o.origin())
+}
+
+// fill sets a cell's worth of pixels to the given palette index.
+func (o Options) fill(img *image.Paletted, x, y int, index uint8) {
+	left := o.origin() + x*o.pitch()
+	top := o.origin() + y*o.pitch()
+	for py := top; py < top+o.CellSize; py++ {
+		for px := left; px < left+o.CellSize; px++ {
+			img.SetColorIndex(px, py, index)
+		}
+	}
+}
+
+// drawGrid fills the entire image with the grid color. Cells are then drawn over the top of it, leaving only the lines.
+func (o Options) drawGrid(img *image.Paletted) {
+	if !o.Grid {
+		return
+	}
+	for i := range img.Pix {
+		img.Pix[i] = gridIndex
+	}
+}
+
+// draw builds an image of the given size in cells, asking index for the palette index of each cell.
+func (o Options) draw(width, height int, index func(x, y int) uint8) *image.Paletted {
+	if o.CellSize < 1 {
+		o.CellSize = 1
+	}
+	img := image.NewPaletted(o.bounds(width, height), o.palette())
+	o.drawGrid(img)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			o.fill(img, x, y, index(x, y))
+		}
+	}
+	return img
+}
+
+// Image draws the field as a paletted image, one square of CellSize pixels per cell.
+func Image(f *rle.RLEField, opts Options) *image.Paletted {
+	return opts.draw(f.Width, f.Height, func(x, y int) uint8 {
+		if f.Field[y][x] {
+			return aliveIndex
+		}
+		return deadIndex
+	})
+}
+
+// EncodePNG writes the field to w as a PNG image.
+func EncodePNG(w io.Writer, f *rle.RLEField, opts Options) error {
+	return png.Encode(w, Image(f, opts))
+}
+
+// EncodeGIF writes the field to w as a (still) GIF image.
+func EncodeGIF(w io.Writer, f *rle.RLEField, opts Options) error {
+	return gif.Encode(w, Image(f, opts), nil)
+}
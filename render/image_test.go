@@ -0,0 +1,82 @@
+package render_test
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/render"
+	"github.com/makyo/gogol/rle"
+)
+
+func glider() *rle.RLEField {
+	f, err := rle.Unmarshal(`#N Glider
+x = 5, y = 5, rule = B3/S23
+5b$2bo2b$3bob$b3ob$5b!`)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func TestImage(t *testing.T) {
+	Convey("Given a field", t, func() {
+		f := glider()
+
+		Convey("When it is drawn with a grid", func() {
+			img := render.Image(f, render.DefaultOptions())
+
+			Convey("The image should be sized to fit the cells and lines", func() {
+				So(img.Bounds().Dx(), ShouldEqual, 5*9+1)
+				So(img.Bounds().Dy(), ShouldEqual, 5*9+1)
+			})
+
+			Convey("Cells and lines should be drawn in the right colors", func() {
+				So(img.At(0, 0), ShouldResemble, color.Gray{0x40})
+				So(img.At(5, 5), ShouldResemble, color.Black)
+				So(img.At(2*9+5, 1*9+5), ShouldResemble, color.White)
+			})
+		})
+	})
+}
+
+func TestRoundTrip(t *testing.T) {
+	Convey("Given a field", t, func() {
+		f := glider()
+
+		Convey("It should survive a trip through a PNG with a grid", func() {
+			var buf bytes.Buffer
+			So(render.EncodePNG(&buf, f, render.DefaultOptions()), ShouldBeNil)
+			result, err := render.Decode(&buf, render.ImportOptions{})
+			So(err, ShouldBeNil)
+			So(result.Field, ShouldResemble, f.Field)
+		})
+
+		Convey("It should survive a trip through a GIF without a grid", func() {
+			var buf bytes.Buffer
+			opts := render.DefaultOptions()
+			opts.Grid = false
+			opts.CellSize = 3
+			So(render.EncodeGIF(&buf, f, opts), ShouldBeNil)
+			result, err := render.Decode(&buf, render.ImportOptions{})
+			So(err, ShouldBeNil)
+
+			// Without a grid, the empty border can't be detected, so compare just the pattern.
+			So(result.Crop(result.BoundingBox()).Field, ShouldResemble, f.Crop(f.BoundingBox()).Field)
+		})
+
+		Convey("Dark cells on a light background should be detected", func() {
+			var buf bytes.Buffer
+			opts := render.DefaultOptions()
+			opts.Alive = color.Black
+			opts.Dead = color.White
+			opts.GridColor = color.Gray{0xc0}
+			So(render.EncodePNG(&buf, f, opts), ShouldBeNil)
+			result, err := render.Decode(&buf, render.ImportOptions{})
+			So(err, ShouldBeNil)
+			So(result.Field, ShouldResemble, f.Field)
+		})
+	})
+}
@@ -0,0 +1,284 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+
+	"github.com/makyo/gogol/rle"
+)
+
+// Polarity describes which cells of an imported image are considered alive.
+type Polarity int
+
+const (
+	// Auto treats whichever of light or dark cells are less common as alive.
+	Auto Polarity = iota
+
+	// LightAlive treats cells brighter than the threshold as alive.
+	LightAlive
+
+	// DarkAlive treats cells darker than the threshold as alive.
+	DarkAlive
+)
+
+// ImportOptions controls how an image is turned back into a field.
+type ImportOptions struct {
+	// Threshold is the luminance, from 0 to 1, which separates light cells from dark. Zero means 0.5.
+	Threshold float64
+
+	// Polarity decides whether light or dark cells are alive.
+	Polarity Polarity
+
+	// CellSize and GridWidth describe the layout of the cells in pixels. If CellSize is zero, the layout (including the
+	// offsets below) is detected from the image.
+	CellSize, GridWidth int
+
+	// Left and Top are the pixel offset of the first whole cell.
+	Left, Top int
+}
+
+// axisLayout describes where cells fall along one axis of an image.
+type axisLayout struct {
+	origin, cellSize, gridWidth int
+}
+
+func (l axisLayout) pitch() int {
+	return l.cellSize + l.gridWidth
+}
+
+// cells returns how many whole cells fit in the given number of pixels.
+func (l axisLayout) cells(pixels int) int {
+	return (pixels - l.origin + l.gridWidth) / l.pitch()
+}
+
+// Decode reads a PNG, GIF, or JPEG image from r and turns it into a field.
+func Decode(r io.Reader, opts ImportOptions) (*rle.RLEField, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to decode image: %v", err)
+	}
+	return Import(img, opts)
+}
+
+// Import turns an image of a board, such as a screenshot or the output of Image, into a field. Each cell is sampled
+// near its center, so grid lines and antialiasing at the edges of cells are ignored.
+func Import(img image.Image, opts ImportOptions) (*rle.RLEField, error) {
+	if opts.Threshold <= 0 {
+		opts.Threshold = 0.5
+	}
+	b := img.Bounds()
+	at := func(x, y int) color.Color { return img.At(b.Min.X+x, b.Min.Y+y) }
+
+	var xl, yl axisLayout
+	if opts.CellSize > 0 {
+		xl = axisLayout{opts.Left, opts.CellSize, opts.GridWidth}
+		yl = axisLayout{opts.Top, opts.CellSize, opts.GridWidth}
+	} else {
+		var ok bool
+		xl, ok = detectAxis(b.Dx(), b.Dy(), at)
+		if !ok {
+			return nil, fmt.Errorf("Unable to detect the cell size of the image; please specify it")
+		}
+		yl, ok = detectAxis(b.Dy(), b.Dx(), func(i, j int) color.Color { return at(j, i) })
+		if !ok {
+			return nil, fmt.Errorf("Unable to detect the cell size of the image; please specify it")
+		}
+	}
+
+	width := xl.cells(b.Dx())
+	height := yl.cells(b.Dy())
+	if width < 1 || height < 1 {
+		return nil, fmt.Errorf("Image is too small to contain any cells: %dx%d", b.Dx(), b.Dy())
+	}
+
+	// Sample the luminance of every cell.
+	lum := make([][]float64, height)
+	light := 0
+	for y := range lum {
+		lum[y] = make([]float64, width)
+		for x := range lum[y] {
+			lum[y][x] = sample(at, xl.origin+x*xl.pitch(), yl.origin+y*yl.pitch(), xl.cellSize, yl.cellSize)
+			if lum[y][x] > opts.Threshold {
+				light++
+			}
+		}
+	}
+
+	// In auto mode, the background is whichever is more common.
+	if opts.Polarity == Auto {
+		if light*2 > width*height {
+			opts.Polarity = DarkAlive
+		} else {
+			opts.Polarity = LightAlive
+		}
+	}
+
+	f := rle.New(width, height)
+	for y, row := range lum {
+		for x, l := range row {
+			f.Field[y][x] = (l > opts.Threshold) == (opts.Polarity == LightAlive)
+		}
+	}
+	return f, nil
+}
+
+// luminance returns the brightness of a color from 0 to 1.
+func luminance(c color.Color) float64 {
+	return float64(color.Gray16Model.Convert(c).(color.Gray16).Y) / 0xffff
+}
+
+// sample returns the average luminance of the middle half of a cell.
+func sample(at func(x, y int) color.Color, left, top, width, height int) float64 {
+	insetX, insetY := width/4, height/4
+	var total float64
+	count := 0
+	for y := top + insetY; y < top+height-insetY; y++ {
+		for x := left + insetX; x < left+width-insetX; x++ {
+			total += luminance(at(x, y))
+			count++
+		}
+	}
+	if count == 0 {
+		return luminance(at(left, top))
+	}
+	return total / float64(count)
+}
+
+// sameColor compares two colors, allowing for a little noise from lossy formats.
+func sameColor(a, b color.Color) bool {
+	ar, ag, ab, _ := a.RGBA()
+	br, bg, bb, _ := b.RGBA()
+	const tolerance = 0x800
+	diff := func(x, y uint32) bool {
+		if x > y {
+			return x-y > tolerance
+		}
+		return y-x > tolerance
+	}
+	return !diff(ar, br) && !diff(ag, bg) && !diff(ab, bb)
+}
+
+// detectAxis works out the cell layout along one axis of an image with n lines (columns, say) of m pixels each. at(i,
+// j) returns the jth pixel of the ith line.
+//
+// If the image has grid lines, they show up as lines which are entirely one color, evenly spaced. Otherwise, every run
+// of a single color within a line must be a whole number of cells long, so the cell size is the greatest common
+// divisor of all of the run lengths.
+func detectAxis(n, m int, at func(i, j int) color.Color) (axisLayout, bool) {
+	if l, ok := detectGrid(n, m, at); ok {
+		return l, true
+	}
+
+	cellSize := 0
+	origin := -1
+	for j := 0; j < m; j++ {
+		start := 0
+		for i := 1; i <= n; i++ {
+			if i < n && sameColor(at(i, j), at(start, j)) {
+				continue
+			}
+
+			// Runs touching the edges of the image may be cut off, so don't count them.
+			if start > 0 && i < n {
+				cellSize = gcd(cellSize, i-start)
+				if origin < 0 {
+					origin = start
+				}
+			}
+			start = i
+		}
+	}
+	if cellSize < 1 {
+		return axisLayout{}, false
+	}
+	return axisLayout{origin % cellSize, cellSize, 0}, true
+}
+
+// detectGrid looks for evenly spaced lines of a single color.
+func detectGrid(n, m int, at func(i, j int) color.Color) (axisLayout, bool) {
+	// Find the lines which are a single color all the way across.
+	uniform := make([]color.Color, n)
+	for i := 0; i < n; i++ {
+		c := at(i, 0)
+		uniform[i] = c
+		for j := 1; j < m; j++ {
+			if !sameColor(c, at(i, j)) {
+				uniform[i] = nil
+				break
+			}
+		}
+	}
+
+	// The grid color is the most common color among those lines.
+	var gridColor color.Color
+	best := 0
+	for _, c := range uniform {
+		if c == nil {
+			continue
+		}
+		count := 0
+		for _, other := range uniform {
+			if other != nil && sameColor(c, other) {
+				count++
+			}
+		}
+		if count > best {
+			best = count
+			gridColor = c
+		}
+	}
+	if gridColor == nil {
+		return axisLayout{}, false
+	}
+
+	// Collect the runs of grid lines.
+	type run struct{ start, end int }
+	var runs []run
+	for i := 0; i < n; i++ {
+		if uniform[i] == nil || !sameColor(uniform[i], gridColor) {
+			continue
+		}
+		if len(runs) > 0 && runs[len(runs)-1].end == i {
+			runs[len(runs)-1].end++
+		} else {
+			runs = append(runs, run{i, i + 1})
+		}
+	}
+	if len(runs) < 3 {
+		return axisLayout{}, false
+	}
+
+	// Every line must be the same width and every gap between them the same size for this to be a grid.
+	gridWidth := 0
+	for _, r := range runs {
+		if r.start > 0 && r.end < n {
+			gridWidth = r.end - r.start
+			break
+		}
+	}
+	cellSize := runs[1].start - runs[0].end
+	for k, r := range runs {
+		if r.start > 0 && r.end < n && r.end-r.start != gridWidth {
+			return axisLayout{}, false
+		}
+		if k > 0 && r.start-runs[k-1].end != cellSize {
+			return axisLayout{}, false
+		}
+	}
+	if gridWidth < 1 || cellSize <= gridWidth {
+		return axisLayout{}, false
+	}
+	return axisLayout{runs[0].end % (cellSize + gridWidth), cellSize, gridWidth}, true
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,164 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/rle"
+)
+
+// SVGOptions controls how fields are drawn as SVG.
+type SVGOptions struct {
+	Options
+
+	// Labels adds coordinates along the top and left of each field, and a generation number below each frame of a
+	// filmstrip.
+	Labels bool
+
+	// LabelEvery labels only every nth row and column. Zero or one labels all of them.
+	LabelEvery int
+
+	// Gap is the space between the frames of a filmstrip, in cells.
+	Gap int
+}
+
+// panel is a single field to be drawn, along with an optional caption.
+type panel struct {
+	field   *rle.RLEField
+	caption string
+}
+
+// hex returns a color as an SVG hex triplet.
+func hex(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// EncodeSVG writes the field to w as an SVG image.
+func EncodeSVG(w io.Writer, f *rle.RLEField, opts SVGOptions) error {
+	return writeSVG(w, []panel{{field: f}}, opts)
+}
+
+// EncodeFilmstrip runs the model, writing the given number of consecutive generations side by side to w as an SVG
+// image. The current state of the model is the first frame. Each frame is cropped to the smallest rectangle which
+// contains every living cell across all of the frames.
+func EncodeFilmstrip(w io.Writer, m base.Model, generations int, opts SVGOptions) error {
+	var fields []*rle.RLEField
+	var region image.Rectangle
+	for gen := 0; gen < generations; gen++ {
+		if gen > 0 {
+			m.Next()
+		}
+		f := Snapshot(m)
+		left, top, width, height := f.BoundingBox()
+		if width > 0 {
+			region = region.Union(image.Rect(left, top, left+width, top+height))
+		}
+		fields = append(fields, f)
+	}
+	if region.Empty() {
+		region = image.Rect(0, 0, 1, 1)
+	}
+
+	panels := make([]panel, len(fields))
+	for i, f := range fields {
+		panels[i] = panel{
+			field:   f.Crop(region.Min.X, region.Min.Y, region.Dx(), region.Dy()),
+			caption: fmt.Sprintf("Gen %d", i),
+		}
+	}
+	return writeSVG(w, panels, opts)
+}
+
+// writeSVG lays out the panels from left to right.
+func writeSVG(w io.Writer, panels []panel, opts SVGOptions) error {
+	cs := opts.CellSize
+	if cs < 1 {
+		cs = 1
+	}
+	if opts.LabelEvery < 1 {
+		opts.LabelEvery = 1
+	}
+
+	// Leave room for labels around each panel if needed.
+	margin := 0
+	if opts.Labels {
+		margin = cs * 2
+	}
+
+	var out strings.Builder
+	width, height := 0, 0
+	for _, p := range panels {
+		if width > 0 {
+			width += opts.Gap * cs
+		}
+		width += margin + p.field.Width*cs
+		if h := margin*2 + p.field.Height*cs; h > height {
+			height = h
+		}
+	}
+
+	fmt.Fprintf(&out, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	fontSize := float64(cs) * 0.6
+	offset := 0
+	for _, p := range panels {
+		f := p.field
+		left, top := offset+margin, margin
+		fmt.Fprintf(&out, "<g transform=\"translate(%d,%d)\">\n", left, top)
+
+		// The background is drawn as a single rectangle of dead cells...
+		fmt.Fprintf(&out, "<rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", f.Width*cs, f.Height*cs, hex(opts.Dead))
+
+		// ...with living cells drawn over the top, joining runs on a row into a single rectangle.
+		fmt.Fprintf(&out, "<g fill=\"%s\">\n", hex(opts.Alive))
+		for y, row := range f.Field {
+			for x := 0; x < len(row); x++ {
+				if !row[x] {
+					continue
+				}
+				start := x
+				for x < len(row) && row[x] {
+					x++
+				}
+				fmt.Fprintf(&out, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\"/>\n", start*cs, y*cs, (x-start)*cs, cs)
+			}
+		}
+		fmt.Fprint(&out, "</g>\n")
+
+		if opts.Grid {
+			fmt.Fprintf(&out, "<g stroke=\"%s\" stroke-width=\"1\">\n", hex(opts.GridColor))
+			for x := 0; x <= f.Width; x++ {
+				fmt.Fprintf(&out, "<line x1=\"%d\" y1=\"0\" x2=\"%d\" y2=\"%d\"/>\n", x*cs, x*cs, f.Height*cs)
+			}
+			for y := 0; y <= f.Height; y++ {
+				fmt.Fprintf(&out, "<line x1=\"0\" y1=\"%d\" x2=\"%d\" y2=\"%d\"/>\n", y*cs, f.Width*cs, y*cs)
+			}
+			fmt.Fprint(&out, "</g>\n")
+		}
+
+		if opts.Labels {
+			fmt.Fprintf(&out, "<g font-family=\"monospace\" font-size=\"%.1f\" fill=\"%s\" text-anchor=\"middle\">\n", fontSize, hex(opts.GridColor))
+			for x := 0; x < f.Width; x += opts.LabelEvery {
+				fmt.Fprintf(&out, "<text x=\"%.1f\" y=\"%.1f\">%d</text>\n", (float64(x)+0.5)*float64(cs), -fontSize/2, f.Left+x)
+			}
+			for y := 0; y < f.Height; y += opts.LabelEvery {
+				fmt.Fprintf(&out, "<text x=\"%.1f\" y=\"%.1f\">%d</text>\n", -float64(cs), (float64(y)+0.5)*float64(cs)+fontSize/3, f.Top+y)
+			}
+			if p.caption != "" {
+				fmt.Fprintf(&out, "<text x=\"%.1f\" y=\"%.1f\">%s</text>\n", float64(f.Width*cs)/2, float64(f.Height*cs)+fontSize*1.5, p.caption)
+			}
+			fmt.Fprint(&out, "</g>\n")
+		}
+
+		fmt.Fprint(&out, "</g>\n")
+		offset += margin + f.Width*cs + opts.Gap*cs
+	}
+	fmt.Fprint(&out, "</svg>\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
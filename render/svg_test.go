@@ -0,0 +1,102 @@
+package render_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/abrash"
+	"github.com/makyo/gogol/render"
+)
+
+// size returns the width and height given in an SVG's opening tag.
+func size(svg string) (width, height string) {
+	m := regexp.MustCompile(`<svg [^>]*width="(\d+)" height="(\d+)"`).FindStringSubmatch(svg)
+	So(m, ShouldHaveLength, 3)
+	return m[1], m[2]
+}
+
+// cells returns the rectangles drawn for living cells in an SVG, which are the only ones with an x and y.
+func cells(svg string) []string {
+	return regexp.MustCompile(`<rect x="\d+" y="\d+" width="\d+" height="\d+"/>`).FindAllString(svg, -1)
+}
+
+func TestSVG(t *testing.T) {
+	Convey("Given a glider", t, func() {
+		opts := render.SVGOptions{Options: render.DefaultOptions()}
+		opts.CellSize = 10
+		var buf bytes.Buffer
+
+		Convey("The image should be sized to fit the field", func() {
+			So(render.EncodeSVG(&buf, glider(), opts), ShouldBeNil)
+			width, height := size(buf.String())
+			So(width, ShouldEqual, "50")
+			So(height, ShouldEqual, "50")
+		})
+
+		Convey("Runs of living cells on a row should be joined into one rectangle", func() {
+			So(render.EncodeSVG(&buf, glider(), opts), ShouldBeNil)
+			So(cells(buf.String()), ShouldResemble, []string{
+				`<rect x="20" y="10" width="10" height="10"/>`,
+				`<rect x="30" y="20" width="10" height="10"/>`,
+				`<rect x="10" y="30" width="30" height="10"/>`,
+			})
+		})
+
+		Convey("Grid lines should be drawn around every cell", func() {
+			So(render.EncodeSVG(&buf, glider(), opts), ShouldBeNil)
+			So(strings.Count(buf.String(), "<line "), ShouldEqual, 12)
+
+			buf.Reset()
+			opts.Grid = false
+			So(render.EncodeSVG(&buf, glider(), opts), ShouldBeNil)
+			So(buf.String(), ShouldNotContainSubstring, "<line ")
+		})
+
+		Convey("Labels should leave a margin and number only every nth row and column", func() {
+			opts.Labels = true
+			opts.LabelEvery = 2
+			So(render.EncodeSVG(&buf, glider(), opts), ShouldBeNil)
+			width, height := size(buf.String())
+			So(width, ShouldEqual, "70")
+			So(height, ShouldEqual, "90")
+
+			// Columns and rows 0, 2, and 4 are labelled.
+			So(strings.Count(buf.String(), "<text "), ShouldEqual, 6)
+		})
+	})
+
+	Convey("Given a glider on a larger field", t, func() {
+		m := abrash.New(12, 12)
+		m.Ingest(glider())
+		opts := render.SVGOptions{Options: render.DefaultOptions(), Gap: 2}
+		opts.CellSize = 10
+		var buf bytes.Buffer
+
+		Convey("A filmstrip should crop every frame to everywhere the glider went, with gaps between them", func() {
+			So(render.EncodeFilmstrip(&buf, m, 5, opts), ShouldBeNil)
+			svg := buf.String()
+
+			// Over four generations, the glider moves one cell down and to the right, so each frame is 4x4.
+			width, height := size(svg)
+			So(width, ShouldEqual, "280")
+			So(height, ShouldEqual, "40")
+			So(svg, ShouldContainSubstring, `<g transform="translate(60,0)">`)
+			So(svg, ShouldContainSubstring, `<g transform="translate(240,0)">`)
+			So(strings.Count(svg, `<rect width="40" height="40"`), ShouldEqual, 5)
+		})
+
+		Convey("Filmstrip labels should give each frame's generation and the field's coordinates", func() {
+			opts.Labels = true
+			So(render.EncodeFilmstrip(&buf, m, 2, opts), ShouldBeNil)
+			svg := buf.String()
+			So(svg, ShouldContainSubstring, ">Gen 0</text>")
+			So(svg, ShouldContainSubstring, ">Gen 1</text>")
+			So(svg, ShouldContainSubstring, ">4</text>")
+			So(svg, ShouldNotContainSubstring, ">Gen 2</text>")
+		})
+	})
+}
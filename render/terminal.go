@@ -0,0 +1,60 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// Colorize wraps s in the ANSI escape codes needed to print it in the given color on a truecolor terminal.
+func Colorize(s string, c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm%s\x1b[0m", r>>8, g>>8, b>>8, s)
+}
+
+// Fade returns a color part of the way from one color to another, where 0 is from and 1 is to.
+func Fade(from, to color.Color, amount float64) color.Color {
+	fr, fg, fb, _ := from.RGBA()
+	tr, tg, tb, _ := to.RGBA()
+	mix := func(a, b uint32) uint8 {
+		return uint8((float64(a)*(1-amount) + float64(b)*amount) / 0x101)
+	}
+	return color.RGBA{mix(fr, tr), mix(fg, tg), mix(fb, tb), 0xff}
+}
+
+// ColorRuns draws a field stored row by row, a byte per cell, with a space for each cell in state 0 and a • for each
+// cell in any other state, colored by colorOf. Cells for which colorOf returns nil are drawn without a color. Runs of
+// cells in the same state are colored all at once, which keeps the escape codes to a minimum.
+func ColorRuns(states []byte, width int, colorOf func(state byte) color.Color) string {
+	var frame strings.Builder
+	for y := 0; y*width < len(states); y++ {
+		if y > 0 {
+			frame.WriteString("\n")
+		}
+		row := states[y*width : (y+1)*width]
+		for x := 0; x < len(row); {
+			state := row[x]
+			start := x
+			for x < len(row) && row[x] == state {
+				x++
+			}
+			if state == 0 {
+				frame.WriteString(strings.Repeat(" ", x-start))
+			} else if c := colorOf(state); c == nil {
+				frame.WriteString(strings.Repeat("•", x-start))
+			} else {
+				frame.WriteString(Colorize(strings.Repeat("•", x-start), c))
+			}
+		}
+	}
+	return frame.String()
+}
+
+// Decay returns the color of a cell in one of the dying states of a rule with the given number of states, as in
+// Generations rules, fading from one color at the first dying state (2) to another at the last.
+func Decay(dying, faded color.Color, state byte, states int) color.Color {
+	if states <= 3 {
+		return dying
+	}
+	return Fade(dying, faded, float64(state-2)/float64(states-3))
+}
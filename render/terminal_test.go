@@ -0,0 +1,31 @@
+package render_test
+
+import (
+	"image/color"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/render"
+)
+
+func TestColorRuns(t *testing.T) {
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	colorOf := func(state byte) color.Color {
+		if state == 2 {
+			return red
+		}
+		return nil
+	}
+
+	Convey("Runs of cells in the same state should be drawn and colored together", t, func() {
+		So(render.ColorRuns([]byte{0, 1, 1, 2, 2, 2, 0, 0}, 4, colorOf), ShouldEqual,
+			" ••"+render.Colorize("•", red)+"\n"+render.Colorize("••", red)+"  ")
+	})
+
+	Convey("Dying states should fade from one color to the other", t, func() {
+		So(render.Decay(red, color.Black, 2, 3), ShouldEqual, red)
+		So(render.Decay(red, color.Black, 2, 6), ShouldResemble, color.RGBA{0xff, 0x00, 0x00, 0xff})
+		So(render.Decay(red, color.Black, 5, 6), ShouldResemble, color.RGBA{0x00, 0x00, 0x00, 0xff})
+	})
+}
@@ -12,6 +12,174 @@ type RLEField struct {
 	Name, Origin              string
 	Comments, ExtendedRLEData []string
 	Survive, Born             []int
+
+	// States holds the state of each cell for fields with more than two states, and is nil otherwise. Field is kept
+	// up to date with it, with any non-zero state counted as alive.
+	States [][]int
+
+	// Rule is the rulestring from the header. Survive and Born are only set when it is in plain birth/survival
+	// notation (see: https://conwaylife.com/wiki/Rulestring ).
+	Rule string
+}
+
+// SetRule sets the rulestring for the field, also setting Born and Survive if it is in plain birth/survival notation.
+func (f *RLEField) SetRule(rule string) {
+	f.Rule = rule
+	f.Born, f.Survive = nil, nil
+	born, survive, found := strings.Cut(strings.ToUpper(rule), "/")
+	if !found || !strings.HasPrefix(born, "B") || !strings.HasPrefix(survive, "S") {
+		return
+	}
+	born, survive = born[1:], survive[1:]
+	if strings.Trim(born, "012345678") != "" || strings.Trim(survive, "012345678") != "" {
+		return
+	}
+	f.Born, f.Survive = []int{}, []int{}
+	for _, b := range born {
+		f.Born = append(f.Born, int(b-'0'))
+	}
+	for _, s := range survive {
+		f.Survive = append(f.Survive, int(s-'0'))
+	}
+}
+
+// State returns the state of the given cell, which for two-state fields is 1 for living cells and 0 for dead cells.
+func (f *RLEField) State(x, y int) int {
+	if f.States != nil {
+		return f.States[y][x]
+	}
+	if f.Field[y][x] {
+		return 1
+	}
+	return 0
+}
+
+// SetState sets the state of the given cell, turning the field into a multi-state field if needed.
+func (f *RLEField) SetState(x, y, state int) {
+	if f.States == nil && state > 1 {
+		f.makeStates()
+	}
+	if f.States != nil {
+		f.States[y][x] = state
+	}
+	f.Field[y][x] = state != 0
+}
+
+// makeStates turns a two-state field into a multi-state field.
+func (f *RLEField) makeStates() {
+	f.States = make([][]int, len(f.Field))
+	for i, row := range f.Field {
+		f.States[i] = make([]int, len(row))
+		for j, col := range row {
+			if col {
+				f.States[i][j] = 1
+			}
+		}
+	}
+}
+
+// symbol returns the character(s) used to represent a state in the body of the file. Two-state fields use 'b' and
+// 'o', while multi-state fields use '.' for 0, 'A' through 'X' for 1 through 24, and a prefix of 'p' through 'y'
+// before those letters for higher states.
+func (f *RLEField) symbol(state int) string {
+	if f.States == nil {
+		if state == 0 {
+			return "b"
+		}
+		return "o"
+	}
+	if state == 0 {
+		return "."
+	}
+	if state <= 24 {
+		return string(rune('A' + state - 1))
+	}
+	return string(rune('p'+(state-25)/24)) + string(rune('A'+(state-25)%24))
+}
+
+// rulestring returns the rule in the form it should be written to the header.
+func (f *RLEField) rulestring() string {
+	if f.Rule != "" {
+		return f.Rule
+	}
+	var out strings.Builder
+	fmt.Fprint(&out, "B")
+	for _, b := range f.Born {
+		fmt.Fprintf(&out, "%d", b)
+	}
+	fmt.Fprint(&out, "/S")
+	for _, s := range f.Survive {
+		fmt.Fprintf(&out, "%d", s)
+	}
+	return out.String()
+}
+
+// New creates an empty field of the given size following the rules of Conway's Game of Life.
+func New(width, height int) *RLEField {
+	f := &RLEField{
+		Width:   width,
+		Height:  height,
+		Born:    []int{3},
+		Survive: []int{2, 3},
+		Field:   make([][]bool, height),
+	}
+	for i, _ := range f.Field {
+		f.Field[i] = make([]bool, width)
+	}
+	return f
+}
+
+// Crop returns a copy of the given region of the field, wrapping around the edges as the models do.
+func (f *RLEField) Crop(left, top, width, height int) *RLEField {
+	c := New(width, height)
+	c.Name = f.Name
+	c.Origin = f.Origin
+	c.Comments = f.Comments
+	c.Born = f.Born
+	c.Survive = f.Survive
+	c.Rule = f.Rule
+	c.Left = f.Left + left
+	c.Top = f.Top + top
+	if f.Width < 1 || f.Height < 1 {
+		return c
+	}
+	if f.States != nil {
+		c.makeStates()
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c.SetState(x, y, f.State(((left+x)%f.Width+f.Width)%f.Width, ((top+y)%f.Height+f.Height)%f.Height))
+		}
+	}
+	return c
+}
+
+// BoundingBox returns the smallest rectangle containing every living cell. If there are no living cells, the width and height will be zero.
+func (f *RLEField) BoundingBox() (left, top, width, height int) {
+	minX, minY, maxX, maxY := f.Width, f.Height, -1, -1
+	for y, row := range f.Field {
+		for x, col := range row {
+			if !col {
+				continue
+			}
+			if x < minX {
+				minX = x
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if y < minY {
+				minY = y
+			}
+			if y > maxY {
+				maxY = y
+			}
+		}
+	}
+	if maxX < 0 {
+		return 0, 0, 0, 0
+	}
+	return minX, minY, maxX - minX + 1, maxY - minY + 1
 }
 
 // Marshal generates the contents of an RLE file from a given field.
@@ -33,22 +201,14 @@ func (f *RLEField) Marshal() string {
 	fmt.Fprintf(&out, "#R %d  %d\n", f.Left, f.Top)
 
 	// Write the header
-	fmt.Fprintf(&out, "x = %d, y = %d, rule = B", f.Width, f.Height)
-	for _, b := range f.Born {
-		fmt.Fprintf(&out, "%d", b)
-	}
-	fmt.Fprint(&out, "/S")
-	for _, s := range f.Survive {
-		fmt.Fprintf(&out, "%d", s)
-	}
-	fmt.Fprint(&out, "\n")
+	fmt.Fprintf(&out, "x = %d, y = %d, rule = %s\n", f.Width, f.Height, f.rulestring())
 
 	// Write the content
 	var count int
 	var chunks []string
-	for _, row := range f.Field {
+	for y, row := range f.Field {
 		count = 1
-		for x, col := range row {
+		for x, _ := range row {
 
 			// No need to notate dead cells up until the end of the line (or empty lines).
 			toContinue := false
@@ -59,18 +219,14 @@ func (f *RLEField) Marshal() string {
 				break
 			}
 
-			if x < len(row)-1 && row[x+1] == col {
+			state := f.State(x, y)
+			if x < len(row)-1 && f.State(x+1, y) == state {
 				count++
 				continue
 			}
 
-			// 'o' for living cells, 'b' for dead cells. For some reason ?.?
-			var curr string
-			if col {
-				curr = "o"
-			} else {
-				curr = "b"
-			}
+			// 'o' for living cells, 'b' for dead cells. For some reason ?.? Multi-state fields use letters instead.
+			curr := f.symbol(state)
 
 			// Print the count and cell state (or just cell state if count is 1).
 			if count == 1 {
@@ -130,6 +286,9 @@ func Unmarshal(contents string) (*RLEField, error) {
 	lines := strings.Split(contents, "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
 
 		// Check for # lines
 		if line[0] == byte('#') {
@@ -157,8 +316,8 @@ func Unmarshal(contents string) (*RLEField, error) {
 
 			case "#R":
 				// The coordinates of the top-left corner of the pattern.
-				coords := strings.Split(content, " ")
-				if len(coords) == 2 {
+				coords := strings.Fields(content)
+				if len(coords) != 2 {
 					return nil, fmt.Errorf("Malformed # line - #R line should contain integer X and Y values separated by a space: %q", content)
 				}
 				cx, err := strconv.Atoi(coords[0])
@@ -181,9 +340,14 @@ func Unmarshal(contents string) (*RLEField, error) {
 		}
 
 		// Process the header rule
-		if line[0] == byte('x') {
+		if !headerSeen && line[0] == byte('x') {
 			headerSeen = true
+
+			// Some rulestrings contain commas, so the rule is always taken to be the rest of the line.
 			pairs := strings.Split(line, ",")
+			if i := strings.Index(line, "rule"); i >= 0 {
+				pairs = append(strings.Split(strings.TrimSuffix(strings.TrimSpace(line[:i]), ","), ","), line[i:])
+			}
 			for _, pair := range pairs {
 
 				// Process key/value pairs
@@ -210,45 +374,11 @@ func Unmarshal(contents string) (*RLEField, error) {
 					f.Height = height
 
 				case "rule":
-					// Parse the rule in birth/survival notation (see: https://conwaylife.com/wiki/Rulestring )
-					born, survive, found := strings.Cut(v, "/")
-					if !found {
+					// Keep the rule as written, since there are many notations; engines which understand it will parse it.
+					if v == "" {
 						return nil, fmt.Errorf("Malformed header line - must take the form 'x = m, y = n' with an optional ',  rule = B#/S#': %q", line)
 					}
-
-					// Get the parts
-					_, born, found = strings.Cut(born, "B")
-					if !found {
-						return nil, fmt.Errorf("Malformed header line - must take the form 'x = m, y = n' with an optional ',  rule = B#/S#': %q", line)
-					}
-					_, survive, found = strings.Cut(survive, "S")
-					if !found {
-						return nil, fmt.Errorf("Malformed header line - must take the form 'x = m, y = n' with an optional ',  rule = B#/S#': %q", line)
-					}
-
-					// Build the list of values
-					f.Born = []int{}
-					f.Survive = []int{}
-					if len(born) > 0 {
-						_, err := strconv.Atoi(born)
-						if err != nil {
-							return nil, fmt.Errorf("Malformed header line - must take the form 'x = m, y = n' with an optional ',  rule = B#/S#': %q", line)
-						}
-						for _, b := range born {
-							s, _ := strconv.Atoi(string(b))
-							f.Born = append(f.Born, s)
-						}
-					}
-					if len(survive) > 0 {
-						_, err := strconv.Atoi(survive)
-						if err != nil {
-							return nil, fmt.Errorf("Malformed header line - must take the form 'x = m, y = n' with an optional ',  rule = B#/S#': %q", line)
-						}
-						for _, s := range survive {
-							s, _ := strconv.Atoi(string(s))
-							f.Survive = append(f.Survive, s)
-						}
-					}
+					f.SetRule(v)
 
 				default:
 					return nil, fmt.Errorf("Malformed header line - must take the form 'x = m, y = n' with an optional ',  rule = B#/S#': %q", line)
@@ -271,7 +401,11 @@ func Unmarshal(contents string) (*RLEField, error) {
 		// Process the rule itself, but only if we already have a header.
 		if headerSeen {
 			count := 0
+			var prefix rune
 			for _, char := range line {
+				if prefix != 0 && (char < 'A' || char > 'X') {
+					return nil, fmt.Errorf("Malformed rule - expected a state after '%c' in rule definition", prefix)
+				}
 				switch string(char) {
 				case "b":
 					// Dead.
@@ -279,7 +413,7 @@ func Unmarshal(contents string) (*RLEField, error) {
 						count = 1
 					}
 					for i := 0; i < count; i++ {
-						f.Field[y][x] = false
+						f.SetState(x, y, 0)
 						x++
 					}
 					count = 0
@@ -290,11 +424,37 @@ func Unmarshal(contents string) (*RLEField, error) {
 						count = 1
 					}
 					for i := 0; i < count; i++ {
-						f.Field[y][x] = true
+						f.SetState(x, y, 1)
 						x++
 					}
 					count = 0
 
+				case ".", "A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X":
+					// Multi-state cells.
+					state := 0
+					if char != '.' {
+						state = int(char-'A') + 1
+					}
+					if prefix != 0 {
+						state += 24 + int(prefix-'p')*24
+					}
+					prefix = 0
+					if f.States == nil {
+						f.makeStates()
+					}
+					if count == 0 {
+						count = 1
+					}
+					for i := 0; i < count; i++ {
+						f.SetState(x, y, state)
+						x++
+					}
+					count = 0
+
+				case "p", "q", "r", "s", "t", "u", "v", "w", "x", "y":
+					// Prefix for states above 24.
+					prefix = char
+
 				case "$":
 					// End of line.
 					if count == 0 {
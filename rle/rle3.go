@@ -0,0 +1,244 @@
+package rle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RLE3Field is a three-dimensional pattern in the RLE3 format used by Golly's 3D.lua, which extends RLE by ending each
+// layer with a '/' in the same way each row ends with a '$':
+//
+//	3D version=1 size=30 pos=0,0,0
+//	# A comment
+//	x=3 y=3 z=2 rule=3D4,5/5
+//	bo$3o/2bo$o!
+type RLE3Field struct {
+	Width, Height, Depth int
+
+	// Cells holds the field layer by layer, then row by row, so that a cell is at Cells[z][y][x].
+	Cells [][][]bool
+
+	// Size is the size of the cube the pattern was saved from, if given, and Left, Top, and Front the position of the
+	// pattern within it.
+	Size, Left, Top, Front int
+
+	Comments []string
+	Rule     string
+}
+
+// New3 creates an empty three-dimensional field of the given size.
+func New3(width, height, depth int) *RLE3Field {
+	f := &RLE3Field{
+		Width:  width,
+		Height: height,
+		Depth:  depth,
+		Cells:  make([][][]bool, depth),
+	}
+	for z := range f.Cells {
+		f.Cells[z] = make([][]bool, height)
+		for y := range f.Cells[z] {
+			f.Cells[z][y] = make([]bool, width)
+		}
+	}
+	return f
+}
+
+// Marshal generates the contents of an RLE3 file from a given field.
+func (f *RLE3Field) Marshal() string {
+	var out strings.Builder
+	size := f.Size
+	if size < f.Width || size < f.Height || size < f.Depth {
+		size = f.Width
+		if f.Height > size {
+			size = f.Height
+		}
+		if f.Depth > size {
+			size = f.Depth
+		}
+	}
+	fmt.Fprintf(&out, "3D version=1 size=%d pos=%d,%d,%d\n", size, f.Left, f.Top, f.Front)
+	for _, comment := range f.Comments {
+		fmt.Fprintf(&out, "# %s\n", comment)
+	}
+	fmt.Fprintf(&out, "x=%d y=%d z=%d", f.Width, f.Height, f.Depth)
+	if f.Rule != "" {
+		fmt.Fprintf(&out, " rule=%s", f.Rule)
+	}
+	fmt.Fprint(&out, "\n")
+
+	// Build the body as a list of runs, leaving off dead cells at the ends of rows and empty rows at the ends of
+	// layers, then collapse the row and layer ends which pile up.
+	var chunks []string
+	for _, layer := range f.Cells {
+		for _, row := range layer {
+			end := len(row)
+			for end > 0 && !row[end-1] {
+				end--
+			}
+			for x := 0; x < end; {
+				count := 1
+				for x+count < end && row[x+count] == row[x] {
+					count++
+				}
+				symbol := "b"
+				if row[x] {
+					symbol = "o"
+				}
+				if count > 1 {
+					symbol = strconv.Itoa(count) + symbol
+				}
+				chunks = append(chunks, symbol)
+				x += count
+			}
+			chunks = append(chunks, "$")
+		}
+		chunks = append(chunks, "/")
+	}
+
+	rows, layers, lineLen := 0, 0, 0
+	for _, chunk := range chunks {
+		switch chunk {
+		case "$":
+			rows++
+			continue
+		case "/":
+			rows = 0
+			layers++
+			continue
+		}
+		prefix := ""
+		if layers > 0 {
+			prefix = "/"
+			if layers > 1 {
+				prefix = strconv.Itoa(layers) + "/"
+			}
+			layers = 0
+		}
+		if rows > 0 {
+			if rows > 1 {
+				prefix += strconv.Itoa(rows)
+			}
+			prefix += "$"
+			rows = 0
+		}
+		chunk = prefix + chunk
+		lineLen += len(chunk)
+		if lineLen > 70 {
+			chunk = "\n" + chunk
+			lineLen = len(chunk)
+		}
+		fmt.Fprint(&out, chunk)
+	}
+	fmt.Fprint(&out, "!\n")
+	return out.String()
+}
+
+// Unmarshal3 builds a three-dimensional field from the contents of an RLE3 file.
+func Unmarshal3(contents string) (*RLE3Field, error) {
+	var f *RLE3Field
+	versionSeen := false
+	x, y, z, count := 0, 0, 0, 0
+
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// The first line says which version of the format this is, and where the pattern sat when it was saved.
+		if !versionSeen {
+			fields := strings.Fields(line)
+			if fields[0] != "3D" {
+				return nil, fmt.Errorf("Malformed RLE3 - must start with a '3D' line: %q", line)
+			}
+			versionSeen = true
+			f = &RLE3Field{}
+			for _, field := range fields[1:] {
+				k, v, _ := strings.Cut(field, "=")
+				switch k {
+				case "size":
+					f.Size, _ = strconv.Atoi(v)
+				case "pos":
+					if _, err := fmt.Sscanf(v, "%d,%d,%d", &f.Left, &f.Top, &f.Front); err != nil {
+						return nil, fmt.Errorf("Malformed RLE3 - pos must be three integers separated by commas: %q", line)
+					}
+				}
+			}
+			continue
+		}
+
+		if line[0] == '#' {
+			f.Comments = append(f.Comments, strings.TrimSpace(line[1:]))
+			continue
+		}
+
+		// The header gives the size of the pattern and its rule.
+		if f.Cells == nil {
+			width, height, depth := -1, -1, -1
+			for _, field := range strings.Fields(line) {
+				k, v, found := strings.Cut(field, "=")
+				if !found {
+					return nil, fmt.Errorf("Malformed header line - must take the form 'x=l y=m z=n' with an optional ' rule=...': %q", line)
+				}
+				var err error
+				switch k {
+				case "x":
+					width, err = strconv.Atoi(v)
+				case "y":
+					height, err = strconv.Atoi(v)
+				case "z":
+					depth, err = strconv.Atoi(v)
+				case "rule":
+					f.Rule = v
+				}
+				if err != nil {
+					return nil, fmt.Errorf("Malformed header line - must take the form 'x=l y=m z=n' with an optional ' rule=...': %q", line)
+				}
+			}
+			if width < 1 || height < 1 || depth < 1 {
+				return nil, fmt.Errorf("Malformed header line - width, height, and depth must be positive: %q", line)
+			}
+			f.Width, f.Height, f.Depth, f.Cells = width, height, depth, New3(width, height, depth).Cells
+			continue
+		}
+
+		for _, char := range line {
+			if char >= '0' && char <= '9' {
+				count = count*10 + int(char-'0')
+				continue
+			}
+			if count == 0 {
+				count = 1
+			}
+			switch char {
+			case 'b', '.':
+				x += count
+			case 'o':
+				for i := 0; i < count; i++ {
+					if x >= f.Width || y >= f.Height || z >= f.Depth {
+						return nil, fmt.Errorf("Malformed RLE3 - cell outside of the pattern at %d,%d,%d", x, y, z)
+					}
+					f.Cells[z][y][x] = true
+					x++
+				}
+			case '$':
+				x = 0
+				y += count
+			case '/':
+				x, y = 0, 0
+				z += count
+			case '!':
+				return f, nil
+			default:
+				return nil, fmt.Errorf("Malformed RLE3 - unexpected character '%c' in pattern", char)
+			}
+			count = 0
+		}
+	}
+
+	if f == nil || f.Cells == nil {
+		return nil, fmt.Errorf("Malformed RLE3 - no header")
+	}
+	return f, nil
+}
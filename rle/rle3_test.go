@@ -0,0 +1,59 @@
+package rle_test
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/rle"
+)
+
+func TestRLE3(t *testing.T) {
+	Convey("When unmarshalling the contents of an RLE3 file", t, func() {
+		f, err := rle.Unmarshal3(`3D version=1 size=30 pos=1,2,3
+# A comment
+x=3 y=3 z=4 rule=3D4,5/5
+bo$3o/2bo$o2/2$b2o!`)
+		So(err, ShouldBeNil)
+
+		Convey("It parses the headers", func() {
+			So(f.Width, ShouldEqual, 3)
+			So(f.Height, ShouldEqual, 3)
+			So(f.Depth, ShouldEqual, 4)
+			So(f.Size, ShouldEqual, 30)
+			So([]int{f.Left, f.Top, f.Front}, ShouldResemble, []int{1, 2, 3})
+			So(f.Rule, ShouldEqual, "3D4,5/5")
+			So(f.Comments, ShouldResemble, []string{"A comment"})
+		})
+
+		Convey("It parses each layer", func() {
+			So(f.Cells, ShouldResemble, [][][]bool{
+				{{false, true, false}, {true, true, true}, {false, false, false}},
+				{{false, false, true}, {true, false, false}, {false, false, false}},
+				{{false, false, false}, {false, false, false}, {false, false, false}},
+				{{false, false, false}, {false, false, false}, {false, true, true}},
+			})
+		})
+
+		Convey("It should survive a round trip", func() {
+			out := f.Marshal()
+			So(out, ShouldContainSubstring, "3D version=1 size=30 pos=1,2,3\n# A comment\nx=3 y=3 z=4 rule=3D4,5/5\n")
+			g, err := rle.Unmarshal3(out)
+			So(err, ShouldBeNil)
+			So(g.Cells, ShouldResemble, f.Cells)
+		})
+	})
+
+	Convey("Malformed RLE3 files should return errors", t, func() {
+		for _, contents := range []string{
+			"x=3 y=3 z=3\no!",
+			"3D version=1\nx=3 y=3\no!",
+			"3D version=1\nx=1 y=1 z=1\n2o!",
+			"3D version=1\nx=1 y=1 z=1\nq!",
+			"3D version=1",
+		} {
+			_, err := rle.Unmarshal3(contents)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
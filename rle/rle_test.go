@@ -86,3 +86,61 @@ x = 12, y = 12, rule = B3/S23
 		})
 	})
 }
+
+func TestRules(t *testing.T) {
+	Convey("When unmarshalling a pattern with a rule outside of plain birth/survival notation", t, func() {
+		f, err := rle.Unmarshal(`x = 3, y = 1, rule = B2n3/S23-q
+3o!`)
+		Convey("It should keep the rule as written", func() {
+			So(err, ShouldBeNil)
+			So(f.Rule, ShouldEqual, "B2n3/S23-q")
+			So(f.Born, ShouldBeNil)
+			So(f.Survive, ShouldBeNil)
+		})
+
+		Convey("It should write the rule back out when marshalled", func() {
+			So(f.Marshal(), ShouldContainSubstring, "rule = B2n3/S23-q\n")
+		})
+	})
+}
+
+func TestMultiState(t *testing.T) {
+	Convey("When unmarshalling a multi-state pattern", t, func() {
+		f, err := rle.Unmarshal(`x = 4, y = 2, rule = /2/3
+.AB$2pA.rB!`)
+		So(err, ShouldBeNil)
+
+		Convey("It should parse the states", func() {
+			So(f.States, ShouldResemble, [][]int{
+				[]int{0, 1, 2, 0},
+				[]int{25, 25, 0, 74},
+			})
+		})
+
+		Convey("It should keep the field in step with the states", func() {
+			So(f.Field, ShouldResemble, [][]bool{
+				[]bool{false, true, true, false},
+				[]bool{true, true, false, true},
+			})
+		})
+
+		Convey("It should survive a round trip", func() {
+			g, err := rle.Unmarshal(f.Marshal())
+			So(err, ShouldBeNil)
+			So(g.States, ShouldResemble, f.States)
+			So(g.Rule, ShouldEqual, "/2/3")
+		})
+	})
+}
+
+func TestRulesWithCommas(t *testing.T) {
+	Convey("When unmarshalling a pattern whose rule contains commas", t, func() {
+		f, err := rle.Unmarshal(`x = 3, y = 1, rule = R5,C0,M1,S34..58,B34..45,NM
+3o!`)
+		Convey("It should keep the whole rule", func() {
+			So(err, ShouldBeNil)
+			So(f.Width, ShouldEqual, 3)
+			So(f.Rule, ShouldEqual, "R5,C0,M1,S34..58,B34..45,NM")
+		})
+	})
+}
@@ -0,0 +1,145 @@
+package ruleloader
+
+import (
+	"image/color"
+	"math/rand"
+
+	"github.com/makyo/gogol/render"
+	"github.com/makyo/gogol/rle"
+)
+
+// Like Golly's RuleLoader algorithm, this follows rules with any number of states which are described by rule tables
+// or rule trees in .rule files, such as those collected at https://conwaylife.com/wiki/Rule_table_repository . Each
+// cell is a single byte holding its state, with 0 as the background.
+
+type model struct {
+	width  int
+	height int
+	field  []byte
+	rule   *Rule
+
+	// dir is the directory rules are loaded from by name.
+	dir string
+}
+
+// Next evolves the field one generation by gathering up the states of each cell and its neighbors and asking the rule
+// for the cell's next state.
+func (m *model) Next() {
+	next := make([]byte, len(m.field))
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			next[y*m.width+x] = m.nextState(x, y)
+		}
+	}
+	m.field = next
+}
+
+// nextState returns the state the given cell will have in the next generation.
+func (m *model) nextState(x, y int) byte {
+	var hood [9]byte
+	hood[0] = m.field[y*m.width+x]
+	for i, o := range m.rule.hood.offsets {
+		hood[i+1] = m.field[((y+o[1]+m.height)%m.height)*m.width+(x+o[0]+m.width)%m.width]
+	}
+	return m.rule.step.next(hood)
+}
+
+// Size returns the width and height of the field.
+func (m *model) Size() (int, int) {
+	return m.width, m.height
+}
+
+// State returns the state of the given cell.
+func (m *model) State(x, y int) int {
+	return int(m.field[y*m.width+x])
+}
+
+// SetState sets the state of the given cell.
+func (m *model) SetState(x, y, state int) {
+	m.field[y*m.width+x] = byte(state)
+}
+
+// NextState returns the state the given cell would have after the next generation, given its neighbors as they stand.
+func (m *model) NextState(x, y int) int {
+	return int(m.nextState(x, y))
+}
+
+// Populate generates a random field of automata, where each cell has a 1 in 5 chance of being in a state other than 0,
+// with each of those states equally likely.
+func (m *model) Populate() {
+	for i, _ := range m.field {
+		m.field[i] = 0
+		if rand.Intn(5) == 0 {
+			m.field[i] = byte(1 + rand.Intn(m.rule.states-1))
+		}
+	}
+}
+
+// SetRule loads the named rule from the model's rules directory (see Load). Any cells in states which the new rule
+// doesn't have are cleared.
+func (m *model) SetRule(name string) error {
+	r, err := Load(name, m.dir)
+	if err != nil {
+		return err
+	}
+	m.rule = r
+	for i, c := range m.field {
+		if int(c) >= r.states {
+			m.field[i] = 0
+		}
+	}
+	return nil
+}
+
+// Ingest sets the field to the given value. Cells in states which the rule doesn't have are left in state 0, so the
+// pattern's rule should be set first.
+func (m *model) Ingest(f *rle.RLEField) {
+	startX := (m.width - f.Width) / 2
+	startY := (m.height - f.Height) / 2
+	for y, row := range f.Field {
+		for x, _ := range row {
+			state := f.State(x, y)
+			if state == 0 || state >= m.rule.states {
+				continue
+			}
+			m.field[((y+startY+m.height)%m.height)*m.width+(x+startX+m.width)%m.width] = byte(state)
+		}
+	}
+}
+
+// ToggleCell cycles the given cell through every state of the rule.
+func (m *model) ToggleCell(x, y int) {
+	pos := y*m.width + x
+	m.field[pos] = byte((int(m.field[pos]) + 1) % m.rule.states)
+}
+
+// Export returns the current state of the field.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(m.rule.String())
+	for i, c := range m.field {
+		f.SetState(i%m.width, i/m.width, int(c))
+	}
+	return f
+}
+
+// String builds the entire screen's worth of cells to be printed by returning a • for each cell not in state 0,
+// colored as the rule gives, or a space for a cell in state 0.
+func (m *model) String() string {
+	return render.ColorRuns(m.field, m.width, func(state byte) color.Color {
+		return m.rule.colors[state]
+	})
+}
+
+// New creates a model of the given size following the built-in Life rule, which loads other rules by name from the
+// given directory.
+func New(width, height int, dir string) *model {
+	r, _ := Load("Life", dir)
+	return &model{
+		width:  width,
+		height: height,
+		field:  make([]byte, width*height),
+		rule:   r,
+		dir:    dir,
+	}
+}
@@ -0,0 +1,91 @@
+package ruleloader
+
+// A neighborhood lists the offsets of a cell's neighbors, clockwise from the north, which is the order they are given
+// in the transitions of a rule table.
+type neighborhood struct {
+	name    string
+	offsets [][2]int
+
+	// tree is the order in which a rule tree looks at the neighbors, as indices into the offsets.
+	tree []int
+
+	// symmetries maps the name of each symmetry the neighborhood supports to the permutations of the neighbors which
+	// make it up. Permuting the neighbors in any order is handled separately, since there are far too many ways to do
+	// so to list.
+	symmetries map[string][][]int
+}
+
+// permute is the symmetry under which only the number of neighbors in each state matters.
+const permute = "permute"
+
+// rotations returns the permutations which rotate a ring of the given number of neighbors by every multiple of step,
+// optionally along with their reflections.
+func rotations(count, step int, reflect bool) [][]int {
+	var perms [][]int
+	for r := 0; r < count; r += step {
+		perm := make([]int, count)
+		mirror := make([]int, count)
+		for i := range perm {
+			perm[i] = (i + r) % count
+			mirror[i] = (count - i + r) % count
+		}
+		perms = append(perms, perm)
+		if reflect {
+			perms = append(perms, mirror)
+		}
+	}
+	return perms
+}
+
+// symmetries returns the symmetries common to every neighborhood with the given number of neighbors.
+func symmetries(count int) map[string][][]int {
+	return map[string][][]int{
+		"none":               rotations(count, count, false),
+		"reflect_horizontal": rotations(count, count, true),
+		permute:              nil,
+	}
+}
+
+var (
+	moore = &neighborhood{
+		name:    "Moore",
+		offsets: [][2]int{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}},
+		tree:    []int{7, 1, 5, 3, 0, 6, 2, 4},
+	}
+	vonNeumann = &neighborhood{
+		name:    "vonNeumann",
+		offsets: [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}},
+		tree:    []int{0, 3, 1, 2},
+	}
+
+	// Hexagonal neighborhoods are emulated on a square grid in the same way as the hex engine, leaving out the
+	// northeast and southwest neighbors.
+	hexagonal = &neighborhood{
+		name:    "hexagonal",
+		offsets: [][2]int{{0, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 0}, {-1, -1}},
+	}
+
+	neighborhoods = map[string]*neighborhood{}
+)
+
+func init() {
+	moore.symmetries = symmetries(8)
+	moore.symmetries["rotate4"] = rotations(8, 2, false)
+	moore.symmetries["rotate4reflect"] = rotations(8, 2, true)
+	moore.symmetries["rotate8"] = rotations(8, 1, false)
+	moore.symmetries["rotate8reflect"] = rotations(8, 1, true)
+
+	vonNeumann.symmetries = symmetries(4)
+	vonNeumann.symmetries["rotate4"] = rotations(4, 1, false)
+	vonNeumann.symmetries["rotate4reflect"] = rotations(4, 1, true)
+
+	hexagonal.symmetries = symmetries(6)
+	hexagonal.symmetries["rotate2"] = rotations(6, 3, false)
+	hexagonal.symmetries["rotate3"] = rotations(6, 2, false)
+	hexagonal.symmetries["rotate6"] = rotations(6, 1, false)
+	hexagonal.symmetries["rotate6reflect"] = rotations(6, 1, true)
+
+	for _, n := range []*neighborhood{moore, vonNeumann, hexagonal} {
+		neighborhoods[n.name] = n
+	}
+}
@@ -0,0 +1,203 @@
+package ruleloader
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/makyo/gogol/render"
+)
+
+// DefaultDir is the directory which rules are usually loaded from by name, as Golly does with its own rules directory.
+// A pattern with the header rule = Langtons-Loops follows the rule in Langtons-Loops.rule there.
+const DefaultDir = "rules"
+
+// Life is a rule table for Conway's Game of Life, which is built in so that there is always a rule to follow.
+const Life = `@RULE Life
+@TABLE
+n_states:2
+neighborhood:Moore
+symmetries:permute
+var a={0,1}
+var b={0,1}
+var c={0,1}
+var d={0,1}
+var e={0,1}
+var f={0,1}
+var g={0,1}
+var h={0,1}
+0,1,1,1,0,0,0,0,0,1
+1,1,1,0,0,0,0,0,0,1
+1,1,1,1,0,0,0,0,0,1
+1,a,b,c,d,e,f,g,h,0
+`
+
+// A stepper finds the next state of a cell, given its state followed by the states of its neighbors.
+type stepper interface {
+	next(hood [9]byte) byte
+}
+
+// Rule is a rule read from a Golly .rule file, made up of either a rule table or a rule tree, along with the colors
+// used to draw each state.
+type Rule struct {
+	name   string
+	states int
+	hood   *neighborhood
+	step   stepper
+	colors []color.Color
+}
+
+// String returns the name of the rule.
+func (r *Rule) String() string {
+	return r.name
+}
+
+// States returns the total number of states cells may be in.
+func (r *Rule) States() int {
+	return r.states
+}
+
+// Rules are kept once they're loaded, keyed by the file they came from, so that every model following a rule shares
+// one copy of it, along with what its table has worked out so far.
+var (
+	loaded   = map[string]*Rule{}
+	loadedMu sync.Mutex
+)
+
+// Load finds a rule by name. The name may be that of the built-in Life rule, the path to a .rule file, or the name of a
+// .rule file in the given directory. Loading the same rule again returns the same Rule.
+func Load(name, dir string) (*Rule, error) {
+	name = strings.TrimSpace(name)
+	path := name
+	if name != "Life" && !strings.HasSuffix(name, ".rule") {
+		path = filepath.Join(dir, name+".rule")
+	}
+
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+	if r, ok := loaded[path]; ok {
+		return r, nil
+	}
+	contents := Life
+	if name != "Life" {
+		file, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Unknown rule %q: %v", name, err)
+		}
+		contents = string(file)
+	}
+	r, err := Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+	loaded[path] = r
+	return r, nil
+}
+
+// Parse parses the contents of a .rule file, which must have a @RULE section naming the rule and either a @TABLE or a
+// @TREE section describing it, and may have a @COLORS section. Any other sections are ignored.
+func Parse(contents string) (*Rule, error) {
+	sections := map[string][]string{}
+	section := ""
+	for _, line := range strings.Split(contents, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "@") {
+			fields := strings.Fields(line)
+			section = fields[0]
+			if _, ok := sections[section]; ok {
+				return nil, fmt.Errorf("Malformed rule - more than one %s section", section)
+			}
+			sections[section] = fields[1:]
+			continue
+		}
+		if section == "" {
+			return nil, fmt.Errorf("Malformed rule - expected a section before %q", line)
+		}
+		sections[section] = append(sections[section], line)
+	}
+
+	r := &Rule{}
+	if header := sections["@RULE"]; len(header) > 0 {
+		r.name = header[0]
+	} else {
+		return nil, fmt.Errorf("Malformed rule - missing a @RULE name")
+	}
+
+	if lines, ok := sections["@TABLE"]; ok {
+		t, err := parseTable(lines)
+		if err != nil {
+			return nil, fmt.Errorf("Malformed rule table - %v: %s", err, r.name)
+		}
+		r.states, r.hood, r.step = t.states, t.hood, t
+	} else if lines, ok := sections["@TREE"]; ok {
+		t, err := parseTree(lines)
+		if err != nil {
+			return nil, fmt.Errorf("Malformed rule tree - %v: %s", err, r.name)
+		}
+		r.states, r.hood, r.step = t.states, t.hood, t
+	} else {
+		return nil, fmt.Errorf("Malformed rule - missing a @TABLE or @TREE section: %s", r.name)
+	}
+
+	if err := r.parseColors(sections["@COLORS"]); err != nil {
+		return nil, fmt.Errorf("Malformed rule colors - %v: %s", err, r.name)
+	}
+	return r, nil
+}
+
+// Golly's default colors, used for any states not given in a @COLORS section: the live states fade from red to yellow.
+var (
+	firstColor = color.RGBA{0xff, 0x00, 0x00, 0xff}
+	lastColor  = color.RGBA{0xff, 0xff, 0x00, 0xff}
+)
+
+// gradient colors the live states from one color to another.
+func (r *Rule) gradient(from, to color.Color) {
+	for state := 1; state < r.states; state++ {
+		amount := 0.0
+		if r.states > 2 {
+			amount = float64(state-1) / float64(r.states-2)
+		}
+		r.colors[state] = render.Fade(from, to, amount)
+	}
+}
+
+// parseColors parses the lines of a @COLORS section, each of which either gives the color of a single state, as in
+// 1 255 0 0, or a gradient across all the live states, as in 255 0 0 255 255 0.
+func (r *Rule) parseColors(lines []string) error {
+	r.colors = make([]color.Color, r.states)
+	r.colors[0] = color.Black
+	r.gradient(firstColor, lastColor)
+	for _, line := range lines {
+		var values []uint8
+		for _, field := range strings.Fields(line) {
+			value, err := strconv.ParseUint(field, 10, 8)
+			if err != nil {
+				return fmt.Errorf("bad value in %q", line)
+			}
+			values = append(values, uint8(value))
+		}
+		switch len(values) {
+		case 4:
+			if int(values[0]) >= r.states {
+				return fmt.Errorf("no such state in %q", line)
+			}
+			r.colors[values[0]] = color.RGBA{values[1], values[2], values[3], 0xff}
+		case 6:
+			r.gradient(color.RGBA{values[0], values[1], values[2], 0xff}, color.RGBA{values[3], values[4], values[5], 0xff})
+		default:
+			return fmt.Errorf("expected a state and color or two colors in %q", line)
+		}
+	}
+	return nil
+}
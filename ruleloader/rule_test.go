@@ -0,0 +1,240 @@
+package ruleloader
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/isotropic"
+	"github.com/makyo/gogol/rle"
+	"github.com/makyo/gogol/wireworld"
+)
+
+// Wireworld written as a rule table, using bound and unbound variables.
+const wireWorldTable = `@RULE WireWorld
+@TABLE
+n_states:4
+neighborhood:Moore
+symmetries:permute
+var a={0,1,2,3}
+var b={0,1,2,3}
+var c={0,1,2,3}
+var d={0,1,2,3}
+var e={0,1,2,3}
+var f={0,1,2,3}
+var g={0,1,2,3}
+var h={0,1,2,3}
+var i={0,2,3}
+var j={i}
+var k={i}
+var l={i}
+var m={i}
+var n={i}
+var o={i}
+1,a,b,c,d,e,f,g,h,2
+2,a,b,c,d,e,f,g,h,3
+3,1,i,j,k,l,m,n,o,1
+3,1,1,i,j,k,l,m,n,1
+@COLORS
+1 0 128 255
+2 255 255 255
+3 255 128 0
+`
+
+// A rule tree in which every cell takes on the state of its northern neighbor, so that everything moves south.
+const southTree = `@RULE South
+@TREE
+num_states=2
+num_neighbors=4
+num_nodes=9
+1 0 0
+1 1 1
+2 0 0
+2 1 1
+3 2 2
+3 3 3
+4 4 4
+4 5 5
+5 6 7
+`
+
+// same runs two models for some number of generations from the same pattern, checking that they always match.
+func same(a, b interface {
+	Ingest(*rle.RLEField)
+	Next()
+	Export() *rle.RLEField
+}, pattern string, generations int) {
+	f, err := rle.Unmarshal(pattern)
+	So(err, ShouldBeNil)
+	a.Ingest(f)
+	b.Ingest(f)
+	for i := 0; i < generations; i++ {
+		a.Next()
+		b.Next()
+		ea, eb := a.Export(), b.Export()
+		for y := 0; y < len(ea.Field); y++ {
+			for x := 0; x < len(ea.Field[y]); x++ {
+				So(ea.State(x, y), ShouldEqual, eb.State(x, y))
+			}
+		}
+	}
+}
+
+func TestTables(t *testing.T) {
+	Convey("The built-in Life table should match Conway's Game of Life", t, func() {
+		same(New(24, 24, DefaultDir), isotropic.New(24, 24), `x = 3, y = 3
+b2o$2o$bo!`, 30)
+	})
+
+	Convey("Wireworld written as a table should match the Wireworld engine", t, func() {
+		m := New(16, 9, DefaultDir)
+		r, err := Parse(wireWorldTable)
+		So(err, ShouldBeNil)
+		m.rule = r
+		same(m, wireworld.New(16, 9), `x = 13, y = 7, rule = WireWorld
+BA2C$4.C$3.4C$3.C2.7C$3.4C$4.C$BA2C!`, 20)
+		So(r.colors[1], ShouldResemble, color.RGBA{0x00, 0x80, 0xff, 0xff})
+	})
+
+	Convey("Models sharing a table should be able to run at the same time", t, func() {
+		r, err := Parse(wireWorldTable)
+		So(err, ShouldBeNil)
+		f, err := rle.Unmarshal(`x = 13, y = 7, rule = WireWorld
+BA2C$4.C$3.4C$3.C2.7C$3.4C$4.C$BA2C!`)
+		So(err, ShouldBeNil)
+		want := wireworld.New(16, 9)
+		So(want.SetRule(f.Rule), ShouldBeNil)
+		want.Ingest(f)
+		for i := 0; i < 20; i++ {
+			want.Next()
+		}
+
+		got := make([]*rle.RLEField, 4)
+		var wg sync.WaitGroup
+		for i := range got {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				m := New(16, 9, DefaultDir)
+				m.rule = r
+				m.Ingest(f)
+				for gen := 0; gen < 20; gen++ {
+					m.Next()
+				}
+				got[i] = m.Export()
+			}(i)
+		}
+		wg.Wait()
+		for _, g := range got {
+			So(g.States, ShouldResemble, want.Export().States)
+		}
+	})
+
+	Convey("Symmetries should apply each transition in every orientation", t, func() {
+		r, err := Parse(`@RULE Plus
+@TABLE
+n_states:2
+neighborhood:vonNeumann
+symmetries:rotate4
+010001`)
+		So(err, ShouldBeNil)
+		So(r.step.next([9]byte{0, 1, 0, 0, 0}), ShouldEqual, 1)
+		So(r.step.next([9]byte{0, 0, 0, 1, 0}), ShouldEqual, 1)
+		So(r.step.next([9]byte{0, 1, 1, 0, 0}), ShouldEqual, 0)
+		So(r.step.next([9]byte{1, 0, 0, 0, 0}), ShouldEqual, 1)
+	})
+
+	Convey("Variables which appear more than once should be bound to the same state", t, func() {
+		r, err := Parse(`@RULE Bound
+@TABLE
+n_states:3
+neighborhood:hexagonal
+symmetries:none
+var a={1,2}
+0,a,0,0,a,0,0,a`)
+		So(err, ShouldBeNil)
+		So(r.step.next([9]byte{0, 2, 0, 0, 2, 0, 0}), ShouldEqual, 2)
+		So(r.step.next([9]byte{0, 1, 0, 0, 2, 0, 0}), ShouldEqual, 0)
+	})
+
+	Convey("Malformed tables should return errors", t, func() {
+		for _, contents := range []string{
+			"@TABLE\nn_states:2\n0,0,0,0,0,0,0,0,0,0",
+			"@RULE A\n@COLORS\n1 255 0 0",
+			"@RULE A\n@TABLE\nneighborhood:Moore\n0,0,0,0,0,0,0,0,0,0",
+			"@RULE A\n@TABLE\nn_states:2\nneighborhood:Moore\n0,0,0,0,0,0,0,0,0",
+			"@RULE A\n@TABLE\nn_states:2\nneighborhood:Moore\n0,0,0,0,0,0,0,0,0,2",
+			"@RULE A\n@TABLE\nn_states:2\nneighborhood:Moore\n0,0,0,0,0,0,0,0,0,a",
+			"@RULE A\n@TABLE\nn_states:2\nneighborhood:hexagonal\nsymmetries:rotate4\n",
+			"@RULE A\n@TABLE\nn_states:2\nneighborhood:Moore\n@COLORS\n2 255 0 0",
+		} {
+			_, err := Parse(contents)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestTrees(t *testing.T) {
+	Convey("Given a rule tree", t, func() {
+		r, err := Parse(southTree)
+		So(err, ShouldBeNil)
+
+		Convey("It should look at the neighbors in Golly's order", func() {
+			So(r.step.next([9]byte{0, 1, 0, 0, 0}), ShouldEqual, 1)
+			So(r.step.next([9]byte{1, 0, 1, 1, 1}), ShouldEqual, 0)
+		})
+	})
+
+	Convey("Malformed trees should return errors", t, func() {
+		for _, contents := range []string{
+			"@RULE A\n@TREE\nnum_states=2\nnum_neighbors=4\nnum_nodes=1\n1 0 0",
+			"@RULE A\n@TREE\nnum_states=2\nnum_neighbors=6\nnum_nodes=1\n1 0 0",
+			"@RULE A\n@TREE\nnum_states=2\nnum_neighbors=4\nnum_nodes=2\n1 0 0\n3 0 0",
+			"@RULE A\n@TREE\nnum_states=2\nnum_neighbors=4\nnum_nodes=2\n1 0 2",
+		} {
+			_, err := Parse(contents)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	Convey("Given a directory of rules", t, func() {
+		dir := t.TempDir()
+		So(os.WriteFile(filepath.Join(dir, "South.rule"), []byte(southTree), 0644), ShouldBeNil)
+
+		Convey("A pattern should follow the rule named in its header", func() {
+			f, err := rle.Unmarshal(`x = 1, y = 1, rule = South
+o!`)
+			So(err, ShouldBeNil)
+			m := New(5, 5, dir)
+			So(m.SetRule(f.Rule), ShouldBeNil)
+			m.Ingest(f)
+			So(m.rule.String(), ShouldEqual, "South")
+
+			m.Next()
+			So(m.Export().State(2, 3), ShouldEqual, 1)
+			So(m.Export().State(2, 2), ShouldEqual, 0)
+		})
+
+		Convey("Unknown rules should return errors", func() {
+			So(New(5, 5, dir).SetRule("North"), ShouldNotBeNil)
+		})
+
+		Convey("Models following the same rule should share it", func() {
+			m, n := New(5, 5, dir), New(5, 5, dir)
+			So(m.rule, ShouldPointTo, n.rule)
+			So(m.SetRule("South"), ShouldBeNil)
+			So(n.SetRule(filepath.Join(dir, "South.rule")), ShouldBeNil)
+			So(m.rule, ShouldPointTo, n.rule)
+		})
+
+		Convey("Rules for other algorithms should return errors rather than being ignored", func() {
+			So(New(5, 5, dir).SetRule("B3/S23"), ShouldNotBeNil)
+		})
+	})
+}
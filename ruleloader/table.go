@@ -0,0 +1,273 @@
+package ruleloader
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// cacheSize is the most neighborhoods a table remembers the results for at once.
+const cacheSize = 1 << 18
+
+// A table is a list of transitions, each giving the next state for a cell whose state and neighbors match it. The first
+// transition to match under any of the table's symmetries wins, and cells which match no transition stay as they are
+// (see: https://golly.sourceforge.io/Help/formats.html#table ).
+type table struct {
+	states       int
+	hood         *neighborhood
+	symmetry     string
+	transitions  []transition
+	permutations [][]int
+
+	// Looking through the transitions is slow, so the result for each neighborhood is remembered once it's found. There
+	// are far too many neighborhoods to work them all out up front, so the cache is emptied whenever it fills up, and
+	// since models following the same rule share its table (see Load), it's guarded by a lock.
+	cache map[[9]byte]byte
+	mu    sync.RWMutex
+}
+
+// A transition holds the states allowed for the cell and each of its neighbors, followed by its next state.
+type transition struct {
+	inputs []input
+
+	// output is the next state, unless bound is zero or more, in which case the next state is whatever state the input
+	// at that position took.
+	output byte
+	bound  int
+}
+
+// An input is a set of states allowed at one position of a transition. Variables are bound, so if the same variable
+// appears more than once, every position it appears at must be in the same state. bound is the first position the
+// variable appeared at, or -1 if this is the first.
+type input struct {
+	allowed []bool
+	bound   int
+}
+
+// parseTable parses the lines of a @TABLE section.
+func parseTable(lines []string) (*table, error) {
+	t := &table{hood: moore, symmetry: "none", cache: map[[9]byte]byte{}}
+	vars := map[string][]int{}
+	for _, line := range lines {
+		key, value, found := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		switch {
+		case found && (key == "n_states" || key == "num_states"):
+			states, err := strconv.Atoi(value)
+			if err != nil || states < 2 || states > 256 {
+				return nil, fmt.Errorf("bad number of states %q", value)
+			}
+			t.states = states
+		case found && key == "neighborhood":
+			hood, ok := neighborhoods[value]
+			if !ok {
+				return nil, fmt.Errorf("unsupported neighborhood %q", value)
+			}
+			t.hood = hood
+		case found && key == "symmetries":
+			t.symmetry = value
+		case strings.HasPrefix(line, "var "):
+			name, states, err := t.parseVar(strings.TrimPrefix(line, "var "), vars)
+			if err != nil {
+				return nil, err
+			}
+			vars[name] = states
+		default:
+			tr, err := t.parseTransition(line, vars)
+			if err != nil {
+				return nil, err
+			}
+			t.transitions = append(t.transitions, tr)
+		}
+	}
+
+	if t.states == 0 {
+		return nil, fmt.Errorf("missing n_states")
+	}
+	perms, ok := t.hood.symmetries[t.symmetry]
+	if !ok {
+		return nil, fmt.Errorf("unsupported symmetries %q for the %s neighborhood", t.symmetry, t.hood.name)
+	}
+	t.permutations = perms
+	return t, nil
+}
+
+// parseState parses a single state, which must be less than the number of states in the table.
+func (t *table) parseState(token string) (int, error) {
+	state, err := strconv.Atoi(token)
+	if err != nil || state < 0 || state >= t.states {
+		return 0, fmt.Errorf("bad state %q", token)
+	}
+	return state, nil
+}
+
+// parseVar parses a variable definition such as a={0,1,2}, which may include other variables.
+func (t *table) parseVar(def string, vars map[string][]int) (string, []int, error) {
+	name, value, found := strings.Cut(def, "=")
+	name = strings.TrimSpace(name)
+	value = strings.TrimSpace(value)
+	if !found || name == "" || !strings.HasPrefix(value, "{") || !strings.HasSuffix(value, "}") {
+		return "", nil, fmt.Errorf("malformed variable %q", def)
+	}
+	var states []int
+	for _, token := range strings.Split(value[1:len(value)-1], ",") {
+		token = strings.TrimSpace(token)
+		if v, ok := vars[token]; ok {
+			states = append(states, v...)
+			continue
+		}
+		state, err := t.parseState(token)
+		if err != nil {
+			return "", nil, err
+		}
+		states = append(states, state)
+	}
+	return name, states, nil
+}
+
+// parseTransition parses a transition such as 0,1,a,0,0,0,0,0,0,1. If every state and variable is a single character,
+// the commas may be left out.
+func (t *table) parseTransition(line string, vars map[string][]int) (transition, error) {
+	tokens := strings.FieldsFunc(line, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+	size := len(t.hood.offsets) + 2
+	if len(tokens) == 1 && len(line) == size {
+		tokens = strings.Split(line, "")
+	}
+	if len(tokens) != size {
+		return transition{}, fmt.Errorf("transition should have %d states: %q", size, line)
+	}
+
+	tr := transition{bound: -1}
+	seen := map[string]int{}
+	for i, token := range tokens[:size-1] {
+		in := input{allowed: make([]bool, t.states), bound: -1}
+		if states, ok := vars[token]; ok {
+			if first, ok := seen[token]; ok {
+				in.bound = first
+			} else {
+				seen[token] = i
+			}
+			for _, state := range states {
+				in.allowed[state] = true
+			}
+		} else {
+			state, err := t.parseState(token)
+			if err != nil {
+				return transition{}, err
+			}
+			in.allowed[state] = true
+		}
+		tr.inputs = append(tr.inputs, in)
+	}
+
+	output := tokens[size-1]
+	if first, ok := seen[output]; ok {
+		tr.bound = first
+	} else {
+		state, err := t.parseState(output)
+		if err != nil {
+			return transition{}, err
+		}
+		tr.output = byte(state)
+	}
+	return tr, nil
+}
+
+// next returns the next state of a cell, given its state followed by the states of its neighbors.
+func (t *table) next(hood [9]byte) byte {
+	t.mu.RLock()
+	state, ok := t.cache[hood]
+	t.mu.RUnlock()
+	if ok {
+		return state
+	}
+	state = hood[0]
+	for _, tr := range t.transitions {
+		if next, ok := t.match(tr, hood); ok {
+			state = next
+			break
+		}
+	}
+	t.mu.Lock()
+	if len(t.cache) >= cacheSize {
+		t.cache = make(map[[9]byte]byte)
+	}
+	t.cache[hood] = state
+	t.mu.Unlock()
+	return state
+}
+
+// match returns the next state given by a transition if the neighborhood matches it under any of the table's
+// symmetries.
+func (t *table) match(tr transition, hood [9]byte) (byte, bool) {
+	values := make([]byte, len(tr.inputs))
+	if !tr.accepts(0, hood[0], values) {
+		return 0, false
+	}
+	values[0] = hood[0]
+
+	if t.symmetry == permute {
+		var counts [256]int
+		for _, state := range hood[1:len(tr.inputs)] {
+			counts[state]++
+		}
+		if !tr.assign(1, &counts, values) {
+			return 0, false
+		}
+		return tr.result(values), true
+	}
+
+	for _, perm := range t.permutations {
+		matched := true
+		for i, p := range perm {
+			if !tr.accepts(i+1, hood[p+1], values) {
+				matched = false
+				break
+			}
+			values[i+1] = hood[p+1]
+		}
+		if matched {
+			return tr.result(values), true
+		}
+	}
+	return 0, false
+}
+
+// accepts returns whether the input at the given position of the transition allows the given state, given the states
+// already taken by the inputs before it.
+func (tr transition) accepts(pos int, state byte, values []byte) bool {
+	in := tr.inputs[pos]
+	return in.allowed[state] && (in.bound < 0 || values[in.bound] == state)
+}
+
+// assign tries to give each input from the given position onward one of the remaining neighbor states, counted by
+// state, so that the transition matches with the neighbors in some order.
+func (tr transition) assign(pos int, counts *[256]int, values []byte) bool {
+	if pos == len(tr.inputs) {
+		return true
+	}
+	for state := range tr.inputs[pos].allowed {
+		if counts[state] == 0 || !tr.accepts(pos, byte(state), values) {
+			continue
+		}
+		counts[state]--
+		values[pos] = byte(state)
+		ok := tr.assign(pos+1, counts, values)
+		counts[state]++
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// result returns the next state given by the transition once it has matched.
+func (tr transition) result(values []byte) byte {
+	if tr.bound >= 0 {
+		return values[tr.bound]
+	}
+	return tr.output
+}
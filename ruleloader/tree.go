@@ -0,0 +1,95 @@
+package ruleloader
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A tree is a decision tree which looks at the state of each neighbor in turn, and then the cell itself, to find the
+// cell's next state (see: https://golly.sourceforge.io/Help/formats.html#tree ).
+type tree struct {
+	states int
+	hood   *neighborhood
+
+	// nodes holds the children of every node, one after another, with the root last. The children of the nodes at
+	// the bottom of the tree are next states, while the rest are the starts of other nodes.
+	nodes []int
+	root  int
+}
+
+// parseTree parses the lines of a @TREE section.
+func parseTree(lines []string) (*tree, error) {
+	t := &tree{}
+	neighbors, count := 0, 0
+	var starts, levels []int
+	for _, line := range lines {
+		if key, value, found := strings.Cut(line, "="); found {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("bad value %q", line)
+			}
+			switch strings.TrimSpace(key) {
+			case "num_states":
+				t.states = n
+			case "num_neighbors":
+				neighbors = n
+			case "num_nodes":
+				count = n
+			default:
+				return nil, fmt.Errorf("unexpected setting %q", line)
+			}
+			continue
+		}
+
+		if t.states < 2 || t.states > 256 {
+			return nil, fmt.Errorf("bad number of states %d", t.states)
+		}
+		fields := strings.Fields(line)
+		if len(fields) != t.states+1 {
+			return nil, fmt.Errorf("node should have %d children: %q", t.states, line)
+		}
+		level, err := strconv.Atoi(fields[0])
+		if err != nil || level < 1 || level > neighbors+1 {
+			return nil, fmt.Errorf("bad level in node %q", line)
+		}
+		for _, field := range fields[1:] {
+			child, err := strconv.Atoi(field)
+			if err != nil || level == 1 && (child < 0 || child >= t.states) || level > 1 && (child < 0 || child >= len(starts) || levels[child] != level-1) {
+				return nil, fmt.Errorf("bad child in node %q", line)
+			}
+			if level > 1 {
+				child = starts[child]
+			}
+			t.nodes = append(t.nodes, child)
+		}
+		starts = append(starts, len(t.nodes)-t.states)
+		levels = append(levels, level)
+	}
+
+	switch neighbors {
+	case 4:
+		t.hood = vonNeumann
+	case 8:
+		t.hood = moore
+	default:
+		return nil, fmt.Errorf("unsupported number of neighbors %d", neighbors)
+	}
+	if len(starts) == 0 || len(starts) != count {
+		return nil, fmt.Errorf("expected %d nodes, found %d", count, len(starts))
+	}
+	if levels[len(levels)-1] != neighbors+1 {
+		return nil, fmt.Errorf("the last node should be at level %d", neighbors+1)
+	}
+	t.root = starts[len(starts)-1]
+	return t, nil
+}
+
+// next returns the next state of a cell, given its state followed by the states of its neighbors.
+func (t *tree) next(hood [9]byte) byte {
+	node := t.root
+	for _, i := range t.hood.tree {
+		node = t.nodes[node+int(hood[i+1])]
+	}
+	return byte(t.nodes[node+int(hood[0])])
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/makyo/gogol/analysis"
+	"github.com/makyo/gogol/base"
+)
+
+// searchCommand runs random soups until they settle and adds up what they leave behind, keeping the totals in a
+// results file which each search adds to.
+//
+//	gogol search [flags]
+func searchCommand(args []string) error {
+	flags := flag.NewFlagSet("search", flag.ExitOnError)
+	algo := flags.String("algo", "prestafford1", "Which algorithm to use, which must follow B3/S23 on a torus")
+	soups := flags.Int("soups", 1000, "Number of soups to run")
+	seed := flags.Int64("seed", 0, "Seed for the soups (0 uses the current time)")
+	symmetry := flags.String("symmetry", "C1", "Symmetry of the soups: C1, C2, C4, D2, D4, or D8")
+	generations := flags.Int("generations", 10000, "Most generations to run each soup before counting it as unstable")
+	size := flags.Int("size", 128, "Width and height of the field each soup is run on")
+	workers := flags.Int("workers", 0, "Number of soups to run at once (0 uses every CPU)")
+	out := flags.String("o", "soups.json", "Results file to add to")
+	flags.Parse(args)
+
+	if _, err := newModel(*algo, *size, *size, defaultAlgoOptions); err != nil {
+		return err
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	results := analysis.NewSearchResults(*symmetry)
+	contents, err := os.ReadFile(*out)
+	if err == nil {
+		if err := json.Unmarshal(contents, results); err != nil {
+			return fmt.Errorf("Malformed results file - %v: %s", err, *out)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	opts := analysis.SearchOptions{
+		Seed:        *seed,
+		Soups:       *soups,
+		Symmetry:    *symmetry,
+		Generations: *generations,
+		Size:        *size,
+		Workers:     *workers,
+	}
+	start := time.Now()
+	err = analysis.Search(opts, func(width, height int) base.Model {
+		m, _ := newModel(*algo, width, height, defaultAlgoOptions)
+		return m
+	}, results)
+	if err != nil {
+		return err
+	}
+	elapsed := time.Since(start)
+
+	contents, err = json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, contents, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Ran %d soups with seed %d in %v (%.0f soups/s)\n", *soups, *seed, elapsed.Round(time.Millisecond), float64(*soups)/elapsed.Seconds())
+	fmt.Print(results)
+	return nil
+}
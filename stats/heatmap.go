@@ -0,0 +1,215 @@
+package stats
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/render"
+	"github.com/makyo/gogol/rle"
+)
+
+// Kinds of activity a heat map can count.
+const (
+	// Births counts the generations in which each cell came alive.
+	Births = "births"
+
+	// Deaths counts the generations in which each cell died.
+	Deaths = "deaths"
+
+	// Alive counts the generations for which each cell was alive.
+	Alive = "alive"
+)
+
+// ramp holds the colors of a heat map, from the least active cells to the most, running from black through blue, red,
+// and yellow to white.
+var ramp = []color.Color{
+	color.Black,
+	color.RGBA{0x20, 0x30, 0xc0, 0xff},
+	color.RGBA{0xd0, 0x20, 0x20, 0xff},
+	color.RGBA{0xff, 0xd0, 0x20, 0xff},
+	color.White,
+}
+
+// HeatMap counts some kind of activity in each cell over a run of a model, to show where the pattern does its work.
+type HeatMap struct {
+	Kind          string
+	Width, Height int
+
+	// Counts holds the count for each cell, row by row.
+	Counts []int
+
+	// CellWidth is the number of columns each cell takes up when the heat map is drawn in the terminal, which should
+	// match the model's (see base.Wide).
+	CellWidth int
+
+	// RowShift, if set, returns how many columns each row is moved to the right when drawn in the terminal, which
+	// should match the model's (see base.Sheared).
+	RowShift func(y int) int
+
+	// previous is the field at the last generation recorded, to find births and deaths from.
+	previous *rle.RLEField
+}
+
+// NewHeatMap creates an empty heat map counting the given kind of activity: births, deaths, or alive.
+func NewHeatMap(kind string) (*HeatMap, error) {
+	switch kind {
+	case Births, Deaths, Alive:
+		return &HeatMap{Kind: kind, CellWidth: 1}, nil
+	}
+	return nil, fmt.Errorf("Unknown kind of heat map %q - must be births, deaths, or alive", kind)
+}
+
+// Reset clears all of the counts, so that the next generation recorded is the first.
+func (h *HeatMap) Reset() {
+	h.Width, h.Height, h.Counts = 0, 0, nil
+	h.previous = nil
+}
+
+// Record adds the model as it is now to the counts. If the field has changed size since the last generation recorded,
+// the counts start again.
+func (h *HeatMap) Record(m base.Model) {
+	f := m.Export()
+	if f.Width != h.Width || f.Height != h.Height {
+		h.Reset()
+		h.Width, h.Height, h.Counts = f.Width, f.Height, make([]int, f.Width*f.Height)
+	}
+	for y, row := range f.Field {
+		for x, alive := range row {
+			switch {
+			case h.Kind == Alive && alive,
+				h.Kind == Births && alive && h.previous != nil && !h.previous.Field[y][x],
+				h.Kind == Deaths && !alive && h.previous != nil && h.previous.Field[y][x]:
+				h.Counts[y*h.Width+x]++
+			}
+		}
+	}
+	h.previous = f
+}
+
+// Run records the model as it is now, then steps it the given number of generations, recording each one.
+func (h *HeatMap) Run(m base.Model, generations int) {
+	h.Record(m)
+	for i := 0; i < generations; i++ {
+		m.Next()
+		h.Record(m)
+	}
+}
+
+// Max returns the highest count of any cell.
+func (h *HeatMap) Max() int {
+	max := 0
+	for _, count := range h.Counts {
+		if count > max {
+			max = count
+		}
+	}
+	return max
+}
+
+// Color returns the color of a count along the ramp, given the highest count. The scale is logarithmic, so that cells
+// which are only now and then active still show up next to those which are always busy.
+func Color(count, max int) color.Color {
+	if count <= 0 || max <= 0 {
+		return ramp[0]
+	}
+	amount := math.Log1p(float64(count)) / math.Log1p(float64(max)) * float64(len(ramp)-1)
+	step := int(amount)
+	if step >= len(ramp)-1 {
+		return ramp[len(ramp)-1]
+	}
+	return render.Fade(ramp[step], ramp[step+1], amount-float64(step))
+}
+
+// Image draws the heat map with each cell as a square of the given size in pixels.
+func (h *HeatMap) Image(cellSize int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, h.Width*cellSize, h.Height*cellSize))
+	max := h.Max()
+	for i, count := range h.Counts {
+		c := Color(count, max)
+		x, y := i%h.Width*cellSize, i/h.Width*cellSize
+		for dy := 0; dy < cellSize; dy++ {
+			for dx := 0; dx < cellSize; dx++ {
+				img.Set(x+dx, y+dy, c)
+			}
+		}
+	}
+	return img
+}
+
+// WritePNG writes the heat map as a PNG with each cell as a square of the given size in pixels.
+func (h *HeatMap) WritePNG(w io.Writer, cellSize int) error {
+	return png.Encode(w, h.Image(cellSize))
+}
+
+// WriteCSV writes the counts as CSV, with a row for each row of the field.
+func (h *HeatMap) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	record := make([]string, h.Width)
+	for y := 0; y < h.Height; y++ {
+		for x := range record {
+			record[x] = strconv.Itoa(h.Counts[y*h.Width+x])
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// String draws the heat map for the terminal, with each cell as a block colored along the ramp.
+func (h *HeatMap) String() string {
+	if h.RowShift != nil {
+		return h.sheared()
+	}
+	var frame strings.Builder
+	max := h.Max()
+	block := strings.Repeat("█", h.CellWidth)
+	for i, count := range h.Counts {
+		if i > 0 && i%h.Width == 0 {
+			frame.WriteString("\n")
+		}
+		if count == 0 {
+			frame.WriteString(strings.Repeat(" ", h.CellWidth))
+			continue
+		}
+		frame.WriteString(render.Colorize(block, Color(count, max)))
+	}
+	return frame.String()
+}
+
+// sheared draws the heat map for the terminal with each row moved to the right as RowShift says. Since a cell may
+// then wrap around from the last column of the screen to the first, each column is colored on its own.
+func (h *HeatMap) sheared() string {
+	var frame strings.Builder
+	max := h.Max()
+	span := h.CellWidth * h.Width
+	line := make([]string, span)
+	for y := 0; y < h.Height; y++ {
+		if y > 0 {
+			frame.WriteString("\n")
+		}
+		for i := range line {
+			line[i] = " "
+		}
+		for x, count := range h.Counts[y*h.Width : (y+1)*h.Width] {
+			if count == 0 {
+				continue
+			}
+			block := render.Colorize("█", Color(count, max))
+			for i := 0; i < h.CellWidth; i++ {
+				line[(h.CellWidth*x+h.RowShift(y)+i)%span] = block
+			}
+		}
+		frame.WriteString(strings.Join(line, ""))
+	}
+	return frame.String()
+}
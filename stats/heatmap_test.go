@@ -0,0 +1,118 @@
+package stats
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"regexp"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/abrash"
+	"github.com/makyo/gogol/hex"
+	"github.com/makyo/gogol/rle"
+)
+
+// blinkerHeat runs a blinker on a small field for four generations, counting the given kind of activity.
+func blinkerHeat(kind string) *HeatMap {
+	f, err := rle.Unmarshal("x = 3, y = 1\n3o!")
+	So(err, ShouldBeNil)
+	m := abrash.New(5, 5)
+	m.Ingest(f)
+	h, err := NewHeatMap(kind)
+	So(err, ShouldBeNil)
+	h.Run(m, 4)
+	return h
+}
+
+func TestHeatMap(t *testing.T) {
+	Convey("Heat maps should count each kind of activity", t, func() {
+		So(blinkerHeat(Alive).Counts, ShouldResemble, []int{
+			0, 0, 0, 0, 0,
+			0, 0, 2, 0, 0,
+			0, 3, 5, 3, 0,
+			0, 0, 2, 0, 0,
+			0, 0, 0, 0, 0,
+		})
+		So(blinkerHeat(Births).Counts, ShouldResemble, []int{
+			0, 0, 0, 0, 0,
+			0, 0, 2, 0, 0,
+			0, 2, 0, 2, 0,
+			0, 0, 2, 0, 0,
+			0, 0, 0, 0, 0,
+		})
+		So(blinkerHeat(Deaths).Counts[5*1+2], ShouldEqual, 2)
+		So(blinkerHeat(Deaths).Counts[5*2+1], ShouldEqual, 2)
+	})
+
+	Convey("Unknown kinds of heat map should return an error", t, func() {
+		_, err := NewHeatMap("lifespans")
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("Colors should run along the ramp from black to white", t, func() {
+		So(Color(0, 10), ShouldEqual, color.Black)
+		So(Color(10, 10), ShouldEqual, color.White)
+		r, g, b, _ := Color(1, 10).RGBA()
+		So(r+g+b, ShouldBeGreaterThan, 0)
+		So(Color(3, 10), ShouldNotResemble, Color(4, 10))
+	})
+
+	Convey("Heat maps should be written as CSV, PNG, and for the terminal", t, func() {
+		h := blinkerHeat(Alive)
+
+		var out bytes.Buffer
+		So(h.WriteCSV(&out), ShouldBeNil)
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		So(lines, ShouldHaveLength, 5)
+		So(lines[2], ShouldEqual, "0,3,5,3,0")
+
+		out.Reset()
+		So(h.WritePNG(&out, 3), ShouldBeNil)
+		img, err := png.Decode(&out)
+		So(err, ShouldBeNil)
+		So(img.Bounds().Dx(), ShouldEqual, 15)
+		r, g, b, _ := img.At(7, 7).RGBA()
+		So([]uint32{r, g, b}, ShouldResemble, []uint32{0xffff, 0xffff, 0xffff})
+
+		h.CellWidth = 2
+		rows := strings.Split(h.String(), "\n")
+		So(rows, ShouldHaveLength, 5)
+		So(rows[0], ShouldEqual, strings.Repeat(" ", 10))
+		So(rows[2], ShouldContainSubstring, "██")
+	})
+
+	Convey("Heat maps of models drawn with their rows moved sideways should line up with the model", t, func() {
+		f, err := rle.Unmarshal("x = 3, y = 2, rule = B2/S34H\n3o$o!")
+		So(err, ShouldBeNil)
+		m := hex.New(6, 5)
+		m.Ingest(f)
+		h, _ := NewHeatMap(Alive)
+		h.CellWidth = m.CellWidth()
+		h.RowShift = m.RowShift
+		h.Record(m)
+
+		colors := regexp.MustCompile("\x1b\\[[0-9;]*m")
+		field := strings.Split(m.String(), "\n")
+		for y, row := range strings.Split(colors.ReplaceAllString(h.String(), ""), "\n") {
+			cells := []rune(row)
+			So(cells, ShouldHaveLength, 12)
+			for x, c := range []rune(field[y]) {
+				if c == '•' {
+					So(cells[x], ShouldEqual, '█')
+					So(cells[(x+1)%12], ShouldEqual, '█')
+				}
+			}
+			So(strings.Count(row, "█"), ShouldEqual, 2*strings.Count(field[y], "•"))
+		}
+	})
+
+	Convey("Heat maps should start again when the field changes size", t, func() {
+		h := blinkerHeat(Alive)
+		h.Record(abrash.New(3, 3))
+		So(h.Width, ShouldEqual, 3)
+		So(h.Max(), ShouldEqual, 0)
+	})
+}
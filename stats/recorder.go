@@ -0,0 +1,116 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/rle"
+)
+
+// A Generation holds the numbers recorded for a single generation of a run.
+type Generation struct {
+	Generation int `json:"generation"`
+	Population int `json:"population"`
+
+	// Births and Deaths are the number of cells which came alive and died since the generation before, and are zero
+	// for the first generation recorded.
+	Births int `json:"births"`
+	Deaths int `json:"deaths"`
+
+	// Left, Top, Width, and Height give the smallest rectangle holding every living cell, without wrapping around the
+	// edges of the field. The width and height are zero if there are no living cells.
+	Left   int `json:"left"`
+	Top    int `json:"top"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Recorder records numbers about each generation of a run of a model, such as its population, to be written out for
+// graphing.
+type Recorder struct {
+	Generations []Generation
+
+	// previous is the field at the last generation recorded, to find births and deaths from.
+	previous *rle.RLEField
+}
+
+// New creates an empty recorder.
+func New() *Recorder {
+	return &Recorder{}
+}
+
+// Reset clears everything recorded, so that the next generation recorded is the first.
+func (r *Recorder) Reset() {
+	r.Generations = nil
+	r.previous = nil
+}
+
+// Record records the model as it is now, as the generation after the last one recorded.
+func (r *Recorder) Record(m base.Model) {
+	f := m.Export()
+	g := Generation{Generation: len(r.Generations)}
+	g.Left, g.Top, g.Width, g.Height = f.BoundingBox()
+	for y, row := range f.Field {
+		for x, alive := range row {
+			if alive {
+				g.Population++
+			}
+
+			// Fields can change size along with the model, in which case there is nothing to compare with.
+			if r.previous == nil || r.previous.Width != f.Width || r.previous.Height != f.Height {
+				continue
+			}
+			if alive && !r.previous.Field[y][x] {
+				g.Births++
+			} else if !alive && r.previous.Field[y][x] {
+				g.Deaths++
+			}
+		}
+	}
+	r.Generations = append(r.Generations, g)
+	r.previous = f
+}
+
+// Run records the model as it is now, then steps it the given number of generations, recording each one.
+func (r *Recorder) Run(m base.Model, generations int) {
+	r.Record(m)
+	for i := 0; i < generations; i++ {
+		m.Next()
+		r.Record(m)
+	}
+}
+
+// header is the first row written to CSV, naming each column.
+var header = []string{"generation", "population", "births", "deaths", "left", "top", "width", "height"}
+
+// WriteCSV writes everything recorded as CSV, with a row for each generation after a header naming the columns.
+func (r *Recorder) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, g := range r.Generations {
+		row := []int{g.Generation, g.Population, g.Births, g.Deaths, g.Left, g.Top, g.Width, g.Height}
+		record := make([]string, len(row))
+		for i, v := range row {
+			record[i] = strconv.Itoa(v)
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteJSON writes everything recorded as a JSON list with an object for each generation.
+func (r *Recorder) WriteJSON(w io.Writer) error {
+	generations := r.Generations
+	if generations == nil {
+		generations = []Generation{}
+	}
+	return json.NewEncoder(w).Encode(generations)
+}
@@ -0,0 +1,69 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/abrash"
+	"github.com/makyo/gogol/rle"
+)
+
+func TestRecorder(t *testing.T) {
+	Convey("Given a recorder and a blinker", t, func() {
+		f, err := rle.Unmarshal("x = 3, y = 1\n3o!")
+		So(err, ShouldBeNil)
+		m := abrash.New(10, 10)
+		m.Ingest(f)
+		r := New()
+		r.Run(m, 2)
+
+		Convey("It should record each generation, starting with the one before the first step", func() {
+			So(r.Generations, ShouldResemble, []Generation{
+				{Generation: 0, Population: 3, Left: 3, Top: 4, Width: 3, Height: 1},
+				{Generation: 1, Population: 3, Births: 2, Deaths: 2, Left: 4, Top: 3, Width: 1, Height: 3},
+				{Generation: 2, Population: 3, Births: 2, Deaths: 2, Left: 3, Top: 4, Width: 3, Height: 1},
+			})
+		})
+
+		Convey("It should write CSV with a header", func() {
+			var out bytes.Buffer
+			So(r.WriteCSV(&out), ShouldBeNil)
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			So(lines, ShouldHaveLength, 4)
+			So(lines[0], ShouldEqual, "generation,population,births,deaths,left,top,width,height")
+			So(lines[2], ShouldEqual, "1,3,2,2,4,3,1,3")
+		})
+
+		Convey("It should write JSON which reads back the same", func() {
+			var out bytes.Buffer
+			So(r.WriteJSON(&out), ShouldBeNil)
+			var generations []Generation
+			So(json.Unmarshal(out.Bytes(), &generations), ShouldBeNil)
+			So(generations, ShouldResemble, r.Generations)
+		})
+
+		Convey("It should start again from the first generation once reset", func() {
+			r.Reset()
+			m.Next()
+			r.Record(m)
+			So(r.Generations, ShouldHaveLength, 1)
+			So(r.Generations[0].Generation, ShouldEqual, 0)
+			So(r.Generations[0].Births, ShouldEqual, 0)
+
+			var out bytes.Buffer
+			r.Reset()
+			So(r.WriteJSON(&out), ShouldBeNil)
+			So(out.String(), ShouldEqual, "[]\n")
+		})
+	})
+
+	Convey("An empty field should have an empty bounding box", t, func() {
+		r := New()
+		r.Record(abrash.New(10, 10))
+		So(r.Generations[0], ShouldResemble, Generation{})
+	})
+}
@@ -0,0 +1,139 @@
+package stochastic
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/makyo/gogol/base"
+)
+
+// Every engine updates every cell at once, and always in the same way. This wraps any model which can work out the
+// next state of a single cell (see base.Local) so that it can be updated in other ways: only some of the cells each
+// generation, one cell at a time in a random order, or with the rule's results flipped now and then. Everything left
+// to chance is drawn from a random number generator with its own seed, so runs can be repeated.
+
+// Update modes.
+const (
+	// Synchronous updates work out the next state of every cell from the field as it stands before changing any of
+	// them, as the engines do.
+	Synchronous = "synchronous"
+
+	// Sequential updates visit the cells one at a time in a random order each generation, so each cell sees the
+	// changes made to those visited before it.
+	Sequential = "sequential"
+)
+
+// Options says how a model should be updated.
+type Options struct {
+	// Mode is either Synchronous or Sequential.
+	Mode string
+
+	// Probability is the chance that each cell is updated at all in a given generation. Cells which aren't keep their
+	// state.
+	Probability float64
+
+	// Noise is the chance that a cell the rule would leave in state 1 ends up in state 0, or the other way around. Cells
+	// in other states, such as those dying in Generations rules or living cells of any color but the first in
+	// colorlife, are left alone, and cells flipped from state 0 always end up in state 1.
+	Noise float64
+
+	// Seed seeds the random number generator.
+	Seed int64
+}
+
+// DefaultOptions returns options for updating every cell at once without noise, as the engines do, so that wrapping a
+// model with them changes nothing.
+func DefaultOptions() Options {
+	return Options{
+		Mode:        Synchronous,
+		Probability: 1,
+	}
+}
+
+type model struct {
+	base.Model
+	local base.Local
+	opts  Options
+	rand  *rand.Rand
+}
+
+// next returns the next state of the given cell, with noise.
+func (m *model) next(x, y int) int {
+	state := m.local.NextState(x, y)
+	if state <= 1 && m.opts.Noise > 0 && m.rand.Float64() < m.opts.Noise {
+		state = 1 - state
+	}
+	return state
+}
+
+// Next evolves the field one generation according to the options.
+func (m *model) Next() {
+	width, height := m.local.Size()
+	if m.opts.Mode == Sequential {
+		for _, i := range m.rand.Perm(width * height) {
+			if m.rand.Float64() < m.opts.Probability {
+				m.local.SetState(i%width, i/width, m.next(i%width, i/width))
+			}
+		}
+		return
+	}
+
+	next := make([]int, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if m.rand.Float64() < m.opts.Probability {
+				next[y*width+x] = m.next(x, y)
+			} else {
+				next[y*width+x] = m.local.State(x, y)
+			}
+		}
+	}
+	for i, state := range next {
+		m.local.SetState(i%width, i/width, state)
+	}
+}
+
+// SetRule sets the rule of the wrapped model, if it follows rules other than Conway's Game of Life.
+func (m *model) SetRule(rulestring string) error {
+	if r, ok := m.Model.(base.Ruled); ok {
+		return r.SetRule(rulestring)
+	}
+	return fmt.Errorf("This algorithm only supports B3/S23, not %q", rulestring)
+}
+
+// CellWidth returns the number of columns the wrapped model uses to draw each cell.
+func (m *model) CellWidth() int {
+	if w, ok := m.Model.(base.Wide); ok {
+		return w.CellWidth()
+	}
+	return 1
+}
+
+// RowShift returns how many columns the wrapped model moves the given row to the right when drawing it.
+func (m *model) RowShift(y int) int {
+	if s, ok := m.Model.(base.Sheared); ok {
+		return s.RowShift(y)
+	}
+	return 0
+}
+
+// New wraps the given model so that it is updated according to the given options. The model must implement
+// base.Local.
+func New(m base.Model, opts Options) (*model, error) {
+	local, ok := m.(base.Local)
+	if !ok {
+		return nil, fmt.Errorf("This algorithm doesn't support updating cells one at a time")
+	}
+	if opts.Mode != Synchronous && opts.Mode != Sequential {
+		return nil, fmt.Errorf("Unknown update mode: %q", opts.Mode)
+	}
+	if opts.Probability < 0 || opts.Probability > 1 || opts.Noise < 0 || opts.Noise > 1 {
+		return nil, fmt.Errorf("Probabilities must be between 0 and 1")
+	}
+	return &model{
+		Model: m,
+		local: local,
+		opts:  opts,
+		rand:  rand.New(rand.NewSource(opts.Seed)),
+	}, nil
+}
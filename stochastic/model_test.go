@@ -0,0 +1,121 @@
+package stochastic
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/abrash"
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/colorlife"
+	"github.com/makyo/gogol/generations"
+	"github.com/makyo/gogol/hex"
+	"github.com/makyo/gogol/isotropic"
+	"github.com/makyo/gogol/ltl"
+	"github.com/makyo/gogol/naive2d"
+	"github.com/makyo/gogol/ruleloader"
+	"github.com/makyo/gogol/wireworld"
+)
+
+// run populates a model from the given seed, wraps it with the given options, and returns it after ten generations.
+func run(m base.Model, seed int64, opts Options) string {
+	rand.Seed(seed)
+	m.Populate()
+	s, err := New(m, opts)
+	So(err, ShouldBeNil)
+	for i := 0; i < 10; i++ {
+		s.Next()
+	}
+	return s.String()
+}
+
+func TestNext(t *testing.T) {
+	Convey("The default options should change nothing", t, func() {
+		for name, newModel := range map[string]func() base.Model{
+			"isotropic":   func() base.Model { return isotropic.New(20, 20) },
+			"hex":         func() base.Model { return hex.New(20, 20) },
+			"generations": func() base.Model { m := generations.New(20, 20); m.SetRule("345/2/4"); return m },
+			"naive2d":     func() base.Model { return naive2d.New(20, 20) },
+			"ltl":         func() base.Model { m := ltl.New(20, 20); m.SetRule("R2,C3,M1,S6..11,B7..9,NN"); return m },
+			"wireworld":   func() base.Model { return wireworld.New(20, 20) },
+			"colorlife":   func() base.Model { m := colorlife.New(20, 20); m.SetRule("QuadLife"); return m },
+			"ruleloader":  func() base.Model { return ruleloader.New(20, 20, ruleloader.DefaultDir) },
+		} {
+			Convey(name, func() {
+				m := newModel()
+				rand.Seed(1)
+				m.Populate()
+				for i := 0; i < 10; i++ {
+					m.Next()
+				}
+				So(run(newModel(), 1, DefaultOptions()), ShouldEqual, m.String())
+			})
+		}
+	})
+
+	Convey("Cells which are never updated should never change", t, func() {
+		m := isotropic.New(20, 20)
+		rand.Seed(2)
+		m.Populate()
+		before := m.String()
+		So(run(isotropic.New(20, 20), 2, Options{Mode: Sequential, Probability: 0, Noise: 0.5}), ShouldEqual, before)
+	})
+
+	Convey("Noise should flip cells the rule leaves dead", t, func() {
+		s, err := New(isotropic.New(5, 2), Options{Mode: Synchronous, Probability: 1, Noise: 1})
+		So(err, ShouldBeNil)
+		s.Next()
+		So(s.String(), ShouldEqual, "•••••\n•••••")
+	})
+
+	Convey("Runs with the same seed should be the same, and those with different seeds different", t, func() {
+		for _, opts := range []Options{
+			{Mode: Synchronous, Probability: 0.5},
+			{Mode: Sequential, Probability: 1},
+			{Mode: Synchronous, Probability: 1, Noise: 0.01},
+		} {
+			opts.Seed = 3
+			first := run(isotropic.New(30, 30), 4, opts)
+			So(run(isotropic.New(30, 30), 4, opts), ShouldEqual, first)
+			opts.Seed = 5
+			So(run(isotropic.New(30, 30), 4, opts), ShouldNotEqual, first)
+		}
+	})
+
+	Convey("Updating one cell at a time should let later cells see earlier changes", t, func() {
+		// Under B1/S012345678, a single cell grows by one ring of neighbors each generation when updated all at once,
+		// but when cells are visited in turn, newly born cells give birth to their own neighbors right away.
+		m := isotropic.New(15, 15)
+		So(m.SetRule("B1/S012345678"), ShouldBeNil)
+		m.SetState(7, 7, 1)
+		s, err := New(m, Options{Mode: Sequential, Probability: 1})
+		So(err, ShouldBeNil)
+		s.Next()
+		_, _, width, height := s.Export().BoundingBox()
+		So(width*height, ShouldBeGreaterThan, 9)
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Models and options which can't be used should return errors", t, func() {
+		_, err := New(abrash.New(10, 10), DefaultOptions())
+		So(err, ShouldNotBeNil)
+		for _, opts := range []Options{
+			{Mode: "backwards", Probability: 1},
+			{Mode: Synchronous, Probability: 1.5},
+			{Mode: Sequential, Probability: 1, Noise: -1},
+		} {
+			_, err := New(isotropic.New(10, 10), opts)
+			So(err, ShouldNotBeNil)
+		}
+	})
+
+	Convey("Rules and cell widths should be passed through", t, func() {
+		s, err := New(hex.New(10, 10), DefaultOptions())
+		So(err, ShouldBeNil)
+		So(s.SetRule("B2/S34H"), ShouldBeNil)
+		So(s.SetRule("B3/S23"), ShouldNotBeNil)
+		So(s.CellWidth(), ShouldEqual, 2)
+	})
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/makyo/gogol/render"
+)
+
+// svgCommand writes the state of a model, or a filmstrip of several generations, to an SVG image.
+//
+//	gogol svg [flags] [pattern.rle]
+//
+// If no pattern is given, the field is populated at random.
+func svgCommand(args []string) error {
+	fs := flag.NewFlagSet("svg", flag.ExitOnError)
+	start := modelFlags(fs, 64)
+	out := fs.String("o", "out.svg", "File to write the image to")
+	filmstrip := fs.Int("filmstrip", 0, "Lay out this many consecutive generations side by side")
+	cellSize := fs.Int("cell", 10, "Size of each cell")
+	grid := fs.Bool("grid", true, "Draw grid lines between cells")
+	labels := fs.Bool("labels", false, "Label rows, columns, and generations")
+	labelEvery := fs.Int("label-every", 1, "Label only every nth row and column")
+	gap := fs.Int("gap", 2, "Space between the generations of a filmstrip, in cells")
+	fs.Parse(args)
+
+	m, err := start()
+	if err != nil {
+		return err
+	}
+
+	opts := render.SVGOptions{
+		Options:    render.DefaultOptions(),
+		Labels:     *labels,
+		LabelEvery: *labelEvery,
+		Gap:        *gap,
+	}
+	opts.CellSize = *cellSize
+	opts.Grid = *grid
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if *filmstrip > 0 {
+		err = render.EncodeFilmstrip(file, m, *filmstrip, opts)
+	} else {
+		err = render.EncodeSVG(file, render.Snapshot(m), opts)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,222 @@
+package triangular
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/makyo/gogol/rle"
+)
+
+// Triangles are laid out on the square array in rows, pointing alternately up and down, so that each shares its left
+// and right edges with the cells beside it. A cell points up when the sum of its coordinates is even, in which case its
+// third edge is shared with the cell below it; otherwise it points down, and shares its third edge with the cell above
+// it. The neighborhoods of a cell pointing up, marked ^, are:
+//
+//	  full (12)     edge (3)     vertex (9)
+//	  . o o o .    . . . . .    . o o o .
+//	  o o ^ o o    . o ^ o .    o . ^ . o
+//	  o o o o o    . . o . .    o o . o o
+//
+// and those of cells pointing down are the same, upside down.
+//
+// For the triangles to line up as they wrap around the edges, the field must be an even number of cells in each
+// direction.
+//
+// Under rules with B0, the field may be stored complemented, so that the background is always off. Cells are then
+// alive when their value differs from the background.
+
+// An offset is the position of a neighbor relative to a cell.
+type offset struct {
+	dx, dy int
+}
+
+// edges holds the neighbors sharing an edge with a cell pointing up.
+var edges = []offset{{-1, 0}, {1, 0}, {0, 1}}
+
+// vertices holds the neighbors sharing only a corner with a cell pointing up.
+var vertices = []offset{
+	{-1, -1}, {0, -1}, {1, -1},
+	{-2, 0}, {2, 0},
+	{-2, 1}, {-1, 1}, {1, 1}, {2, 1},
+}
+
+// neighborhoods returns the neighbors of cells pointing up and down for the given neighborhood.
+func neighborhoods(neighborhood string) (up, down []offset) {
+	switch neighborhood {
+	case Edge:
+		up = edges
+	case Vertex:
+		up = vertices
+	default:
+		up = append(append([]offset{}, edges...), vertices...)
+	}
+	for _, o := range up {
+		down = append(down, offset{o.dx, -o.dy})
+	}
+	return up, down
+}
+
+type model struct {
+	width      int
+	height     int
+	field      []byte
+	rule       *Rule
+	background byte
+
+	// up and down hold the neighbors of cells pointing up and down under the current rule.
+	up, down []offset
+}
+
+// pointsUp returns whether the given cell points up.
+func pointsUp(x, y int) bool {
+	return (x+y)%2 == 0
+}
+
+// neighbors counts the living cells around the given cell, wrapping around the edges.
+func (m *model) neighbors(x, y int) int {
+	offsets := m.down
+	if pointsUp(x, y) {
+		offsets = m.up
+	}
+	count := 0
+	for _, o := range offsets {
+		count += int(m.field[((y+o.dy+m.height)%m.height)*m.width+(x+o.dx+m.width)%m.width])
+	}
+	return count
+}
+
+// count returns the number of living neighbors of the given cell, complementing the stored cells when the background
+// is on.
+func (m *model) count(x, y int) int {
+	count := m.neighbors(x, y)
+	if m.background == 1 {
+		count = len(m.up) - count
+	}
+	return count
+}
+
+// Next evolves the field one generation. When the background is on, both the cell and its neighbors are complemented
+// before applying the rule, and the result is stored relative to whatever the background becomes.
+func (m *model) Next() {
+	next := make([]byte, len(m.field))
+	background := m.rule.next(m.background, len(m.up)*int(m.background))
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			pos := y*m.width + x
+			next[pos] = m.rule.next(m.field[pos]^m.background, m.count(x, y)) ^ background
+		}
+	}
+	m.field = next
+	m.background = background
+}
+
+// Size returns the width and height of the field.
+func (m *model) Size() (int, int) {
+	return m.width, m.height
+}
+
+// State returns 1 if the given cell is alive and 0 if it is dead.
+func (m *model) State(x, y int) int {
+	return int(m.field[y*m.width+x] ^ m.background)
+}
+
+// SetState sets the given cell to alive (1) or dead (0).
+func (m *model) SetState(x, y, state int) {
+	m.field[y*m.width+x] = byte(state) ^ m.background
+}
+
+// NextState returns the state the given cell would have after the next generation, given its neighbors as they stand.
+func (m *model) NextState(x, y int) int {
+	return int(m.rule.next(byte(m.State(x, y)), m.count(x, y)))
+}
+
+// Populate generates a random field of automata, where each cell has a 1 in 5 chance of being alive.
+func (m *model) Populate() {
+	m.background = 0
+	for i, _ := range m.field {
+		m.field[i] = 0
+		if rand.Intn(5) == 0 {
+			m.field[i] = 1
+		}
+	}
+}
+
+// SetRule sets the rule the model follows from a rulestring such as B4/S345L.
+func (m *model) SetRule(rulestring string) error {
+	r, err := ParseRule(rulestring)
+	if err != nil {
+		return err
+	}
+	m.rule = r
+	m.up, m.down = neighborhoods(r.neighborhood)
+	return nil
+}
+
+// Ingest sets the field to the given value. The pattern is
+// centered as nearly as it can be while keeping the cells which point up pointing up.
+func (m *model) Ingest(f *rle.RLEField) {
+	startX := (m.width - f.Width) / 2
+	startY := (m.height - f.Height) / 2
+	if !pointsUp(startX, startY) {
+		startX--
+	}
+	for y, row := range f.Field {
+		for x, col := range row {
+			if col {
+				m.field[((y+startY+m.height)%m.height)*m.width+(x+startX+m.width)%m.width] = 1 ^ m.background
+			}
+		}
+	}
+}
+
+// ToggleCell toggles whether the given cell is alive or dead. Since sizes are rounded down to even numbers, the field
+// may be a column or row short of the screen, so cells outside of it are ignored.
+func (m *model) ToggleCell(x, y int) {
+	if x < 0 || x >= m.width || y < 0 || y >= m.height {
+		return
+	}
+	m.field[y*m.width+x] ^= 1
+}
+
+// Export returns the current state of the field.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(m.rule.String())
+	for i, c := range m.field {
+		f.Field[i/m.width][i%m.width] = c != m.background
+	}
+	return f
+}
+
+// String builds the entire screen's worth of cells to be printed by returning a ▲ or ▼ for a living cell, pointing the
+// way the cell does, or a space for a dead cell.
+func (m *model) String() string {
+	var frame strings.Builder
+	for i, c := range m.field {
+		if i > 0 && i%m.width == 0 {
+			frame.WriteString("\n")
+		}
+		switch {
+		case c == m.background:
+			frame.WriteString(" ")
+		case pointsUp(i%m.width, i/m.width):
+			frame.WriteString("▲")
+		default:
+			frame.WriteString("▼")
+		}
+	}
+	return frame.String()
+}
+
+// New creates a model of the given size following the B4/S345L rule. Sizes are rounded down to even numbers.
+func New(width, height int) *model {
+	width -= width % 2
+	height -= height % 2
+	m := &model{
+		width:  width,
+		height: height,
+		field:  make([]byte, width*height),
+	}
+	m.SetRule("B4/S345L")
+	return m
+}
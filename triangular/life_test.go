@@ -0,0 +1,156 @@
+package triangular
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/rle"
+)
+
+// corners returns the corners of the given triangle, measuring across in half triangles and down in rows.
+func corners(x, y int) [3][2]int {
+	if pointsUp(x, y) {
+		return [3][2]int{{x + 1, y}, {x, y + 1}, {x + 2, y + 1}}
+	}
+	return [3][2]int{{x, y}, {x + 2, y}, {x + 1, y + 1}}
+}
+
+// shared counts the corners two triangles have in common.
+func shared(a, b [3][2]int) int {
+	count := 0
+	for _, p := range a {
+		for _, q := range b {
+			if p == q {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestNeighborhoods(t *testing.T) {
+	Convey("Each neighborhood should match the triangles touching a cell", t, func() {
+		for _, n := range []struct {
+			rule   string
+			within func(shared int) bool
+		}{
+			{"B1/SL", func(shared int) bool { return shared > 0 }},
+			{"B1/SLE", func(shared int) bool { return shared == 2 }},
+			{"B1/SLV", func(shared int) bool { return shared == 1 }},
+		} {
+			for _, cell := range [][2]int{{4, 4}, {5, 4}} {
+				m := New(10, 10)
+				So(m.SetRule(n.rule), ShouldBeNil)
+				m.SetState(cell[0], cell[1], 1)
+				m.Next()
+				for y := 0; y < 10; y++ {
+					for x := 0; x < 10; x++ {
+						So(m.State(x, y) == 1, ShouldEqual, cell != [2]int{x, y} && n.within(shared(corners(x, y), corners(cell[0], cell[1]))))
+					}
+				}
+			}
+		}
+	})
+}
+
+func TestParseRule(t *testing.T) {
+	Convey("Rules should be parsed for each neighborhood", t, func() {
+		r, err := ParseRule("b4/s345l")
+		So(err, ShouldBeNil)
+		So(r.neighborhood, ShouldEqual, Full)
+		So(r.survive[5], ShouldBeTrue)
+
+		r, err = ParseRule("B1/S12LE")
+		So(err, ShouldBeNil)
+		So(r.neighborhood, ShouldEqual, Edge)
+
+		r, err = ParseRule("B4/S9ABCL")
+		So(err, ShouldBeNil)
+		So(r.survive[12], ShouldBeTrue)
+	})
+
+	Convey("Rules may include B0", t, func() {
+		r, err := ParseRule("B01/S2LE")
+		So(err, ShouldBeNil)
+		So(r.born[0], ShouldBeTrue)
+	})
+
+	Convey("Malformed rules should return errors", t, func() {
+		for _, rule := range []string{"", "B3/S23", "B4/S345", "B4/S4LE", "B4/SALV", "S1/B2L", "B4S345L"} {
+			_, err := ParseRule(rule)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestModel(t *testing.T) {
+	Convey("Sizes should be rounded down to even numbers", t, func() {
+		m := New(11, 9)
+		So(m.width, ShouldEqual, 10)
+		So(m.height, ShouldEqual, 8)
+	})
+
+	Convey("Ingested patterns should keep their triangles pointing the same way", t, func() {
+		f, err := rle.Unmarshal("x = 2, y = 1, rule = B4/S345L\n2o!")
+		So(err, ShouldBeNil)
+		m := New(11, 8)
+		m.Ingest(f)
+		So(m.String(), ShouldContainSubstring, "\n   ▲▼     \n")
+		g, err := rle.Unmarshal(m.Export().Marshal())
+		So(err, ShouldBeNil)
+		So(g.Rule, ShouldEqual, "B4/S345L")
+	})
+
+	Convey("Toggling cells outside of a field rounded down to even numbers should do nothing", t, func() {
+		m := New(11, 9)
+		m.ToggleCell(10, 3)
+		m.ToggleCell(3, 8)
+		m.ToggleCell(-1, 0)
+		So(m.field, ShouldResemble, make([]byte, 80))
+		m.ToggleCell(9, 7)
+		So(m.State(9, 7), ShouldEqual, 1)
+	})
+}
+
+func TestStrobing(t *testing.T) {
+	Convey("Given a single cell following a rule with B0", t, func() {
+		m := New(16, 16)
+		So(m.SetRule("B01/S2L"), ShouldBeNil)
+		m.ToggleCell(8, 8)
+
+		Convey("The whole background should flash on and off, while being stored as off", func() {
+			m.Next()
+			f := m.Export()
+			So(f.Field[0][0], ShouldBeTrue)
+			So(m.field[0], ShouldEqual, 0)
+
+			m.Next()
+			f = m.Export()
+			So(f.Field[0][0], ShouldBeFalse)
+			So(m.field[0], ShouldEqual, 0)
+		})
+
+		Convey("It should match the rule applied to the cells as they are shown", func() {
+			for i := 0; i < 6; i++ {
+				f := m.Export()
+				m.Next()
+				for y, row := range m.Export().Field {
+					for x, col := range row {
+						offsets := m.down
+						if pointsUp(x, y) {
+							offsets = m.up
+						}
+						count := 0
+						for _, o := range offsets {
+							if f.Field[(y+o.dy+16)%16][(x+o.dx+16)%16] {
+								count++
+							}
+						}
+						So(col, ShouldEqual, !f.Field[y][x] && m.rule.born[count] || f.Field[y][x] && m.rule.survive[count])
+					}
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,79 @@
+package triangular
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Neighborhoods, by the suffix which ends rulestrings using them.
+const (
+	// Edge neighborhoods hold the three triangles which share an edge with a cell.
+	Edge = "LE"
+
+	// Vertex neighborhoods hold the nine triangles which share only a corner with a cell.
+	Vertex = "LV"
+
+	// Full neighborhoods hold all twelve triangles which touch a cell at all.
+	Full = "L"
+)
+
+// Rule is an outer totalistic rule on a triangular grid.
+type Rule struct {
+	name          string
+	neighborhood  string
+	born, survive [13]bool
+}
+
+// String returns the rulestring the rule was parsed from.
+func (r *Rule) String() string {
+	return r.name
+}
+
+// next returns the next state of a cell, given its state and number of living neighbors.
+func (r *Rule) next(state byte, count int) byte {
+	if state == 0 && r.born[count] || state == 1 && r.survive[count] {
+		return 1
+	}
+	return 0
+}
+
+// ParseRule parses a triangular rule in birth/survival notation followed by the neighborhood, as in LifeViewer: L for
+// all twelve neighbors, LE for the three sharing an edge, or LV for the nine sharing only a corner, such as B4/S345L or
+// B1/S12LE. Counts of 10, 11, and 12 are written as A, B, and C. Rules may include B0.
+func ParseRule(rulestring string) (*Rule, error) {
+	r := &Rule{name: strings.TrimSpace(rulestring)}
+	malformed := fmt.Errorf("Malformed rule - must take the form 'B#/S#L', 'B#/S#LE', or 'B#/S#LV' with counts that fit the neighborhood: %q", rulestring)
+
+	spec := strings.ToUpper(r.name)
+	size := 0
+	for _, n := range []struct {
+		suffix string
+		size   int
+	}{{Edge, 3}, {Vertex, 9}, {Full, 12}} {
+		if strings.HasSuffix(spec, n.suffix) {
+			r.neighborhood, size = n.suffix, n.size
+			spec = strings.TrimSuffix(spec, n.suffix)
+			break
+		}
+	}
+	if size == 0 {
+		return nil, malformed
+	}
+	born, survive, found := strings.Cut(spec, "/")
+	if !found || !strings.HasPrefix(born, "B") || !strings.HasPrefix(survive, "S") {
+		return nil, malformed
+	}
+	for _, part := range []struct {
+		counts string
+		table  *[13]bool
+	}{{born[1:], &r.born}, {survive[1:], &r.survive}} {
+		for _, c := range part.counts {
+			count := strings.IndexRune("0123456789ABC", c)
+			if count < 0 || count > size {
+				return nil, malformed
+			}
+			part.table[count] = true
+		}
+	}
+	return r, nil
+}
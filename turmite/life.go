@@ -0,0 +1,210 @@
+package turmite
+
+import (
+	"image/color"
+	"math/rand"
+	"strings"
+
+	"github.com/makyo/gogol/render"
+	"github.com/makyo/gogol/rle"
+)
+
+// Turmites are ants which walk around the field, each reading the color of the cell it's on, painting it, turning,
+// and moving forward one cell every generation. The field is a torus, so ants which walk off one edge come back on the
+// opposite one. Any number of ants can share the field; they take turns in the order they were added, so when two
+// ants meet, the later one sees the first one's paint.
+//
+// Since RLE files only know about cells, ants are saved as states of their own after the colors: the state for an ant
+// is the number of colors, plus the ant's state, color of the cell underneath it, and heading packed together.
+
+// Headings, in clockwise order so that turning is addition.
+const (
+	north = iota
+	east
+	south
+	west
+)
+
+// An ant is a single turmite, with its position, heading, and state.
+type ant struct {
+	x, y    int
+	heading int
+	state   int
+}
+
+type model struct {
+	width  int
+	height int
+	field  []byte
+	ants   []*ant
+	rule   *Rule
+}
+
+// Next moves every ant one step.
+func (m *model) Next() {
+	for _, a := range m.ants {
+		pos := a.y*m.width + a.x
+		act := m.rule.table[a.state][m.field[pos]]
+		m.field[pos] = act.write
+		a.heading = (a.heading + act.turn) % 4
+		a.state = act.next
+		switch a.heading {
+		case north:
+			a.y = (a.y + m.height - 1) % m.height
+		case east:
+			a.x = (a.x + 1) % m.width
+		case south:
+			a.y = (a.y + 1) % m.height
+		case west:
+			a.x = (a.x + m.width - 1) % m.width
+		}
+	}
+}
+
+// Populate clears the field and scatters ants at random positions and headings, one for every 2000 cells or so.
+func (m *model) Populate() {
+	for i := range m.field {
+		m.field[i] = 0
+	}
+	m.ants = nil
+	for i := 0; i <= len(m.field)/2000; i++ {
+		m.ants = append(m.ants, &ant{x: rand.Intn(m.width), y: rand.Intn(m.height), heading: rand.Intn(4)})
+	}
+}
+
+// SetRule sets the rule the ants follow from a rulestring such as RL or {{{1,2,0},{0,8,0}}}. Cells in colors which the
+// new rule doesn't have are cleared, and ants in states it doesn't have start over.
+func (m *model) SetRule(rulestring string) error {
+	r, err := ParseRule(rulestring)
+	if err != nil {
+		return err
+	}
+	m.rule = r
+	for i, c := range m.field {
+		if int(c) >= r.colors {
+			m.field[i] = 0
+		}
+	}
+	for _, a := range m.ants {
+		if a.state >= len(r.table) {
+			a.state = 0
+		}
+	}
+	return nil
+}
+
+// Ingest sets the field to the given value. Which states are colors and which are ants depends on the rule, so the
+// pattern's rule should be set first. Any ants and colors already on the field are cleared. If the pattern has no
+// ants in it, a single ant is placed in the middle of the field, heading north.
+func (m *model) Ingest(f *rle.RLEField) {
+	m.ants = nil
+	for i := range m.field {
+		m.field[i] = 0
+	}
+	startX := (m.width - f.Width) / 2
+	startY := (m.height - f.Height) / 2
+	found := false
+	for y, row := range f.Field {
+		for x, _ := range row {
+			state := f.State(x, y)
+			pos := ((y+startY+m.height)%m.height)*m.width + (x+startX+m.width)%m.width
+			if state < m.rule.colors {
+				if state != 0 {
+					m.field[pos] = byte(state)
+				}
+				continue
+			}
+
+			state -= m.rule.colors
+			heading := state % 4
+			state /= 4
+			if state/m.rule.colors >= len(m.rule.table) {
+				continue
+			}
+			m.field[pos] = byte(state % m.rule.colors)
+			m.ants = append(m.ants, &ant{x: pos % m.width, y: pos / m.width, heading: heading, state: state / m.rule.colors})
+			found = true
+		}
+	}
+	if !found {
+		m.ants = append(m.ants, &ant{x: m.width / 2, y: m.height / 2})
+	}
+}
+
+// ToggleCell adds an ant heading north at the given cell, or removes any ants already there.
+func (m *model) ToggleCell(x, y int) {
+	ants := m.ants[:0]
+	for _, a := range m.ants {
+		if a.x != x || a.y != y {
+			ants = append(ants, a)
+		}
+	}
+	if len(ants) == len(m.ants) {
+		ants = append(ants, &ant{x: x, y: y})
+	}
+	m.ants = ants
+}
+
+// Export returns the current state of the field, including the ants.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(m.rule.String())
+	for i, c := range m.field {
+		f.SetState(i%m.width, i/m.width, int(c))
+	}
+	for _, a := range m.ants {
+		color := int(m.field[a.y*m.width+a.x])
+		f.SetState(a.x, a.y, m.rule.colors+(a.state*m.rule.colors+color)*4+a.heading)
+	}
+	return f
+}
+
+// Colors used for drawing cells: with two colors, painted cells are white, and with more, they fade from yellow to
+// purple. Ants are red.
+var (
+	firstColor = color.RGBA{0xff, 0xd0, 0x00, 0xff}
+	lastColor  = color.RGBA{0x80, 0x20, 0xc0, 0xff}
+	antColor   = color.RGBA{0xff, 0x40, 0x40, 0xff}
+)
+
+// arrows shows which way each ant is heading.
+var arrows = [4]string{north: "^", east: ">", south: "v", west: "<"}
+
+// String builds the entire screen's worth of cells to be printed by returning an arrow for each ant, pointing the way it
+// is heading, a • for a painted cell, colored by its color if there are more than two, or a space for a cell of the
+// first color.
+func (m *model) String() string {
+	ants := map[int]*ant{}
+	for _, a := range m.ants {
+		ants[a.y*m.width+a.x] = a
+	}
+
+	var frame strings.Builder
+	for i, c := range m.field {
+		if i > 0 && i%m.width == 0 {
+			frame.WriteString("\n")
+		}
+		switch {
+		case ants[i] != nil:
+			frame.WriteString(render.Colorize(arrows[ants[i].heading], antColor))
+		case c == 0:
+			frame.WriteString(" ")
+		case m.rule.colors == 2:
+			frame.WriteString("•")
+		default:
+			frame.WriteString(render.Colorize("•", render.Fade(firstColor, lastColor, float64(c-1)/float64(m.rule.colors-2))))
+		}
+	}
+	return frame.String()
+}
+
+// New creates a model of the given size with no ants, following the rules of Langton's ant.
+func New(width, height int) *model {
+	r, _ := ParseRule("RL")
+	return &model{
+		width:  width,
+		height: height,
+		field:  make([]byte, width*height),
+		rule:   r,
+	}
+}
@@ -0,0 +1,155 @@
+package turmite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Turns, as numbered in Ed Pegg's notation for turmites, which Golly uses too.
+const (
+	noTurn    = 1
+	turnRight = 2
+	uTurn     = 4
+	turnLeft  = 8
+)
+
+// turns maps each turn to the number of quarter turns clockwise it makes.
+var turns = map[int]int{noTurn: 0, turnRight: 1, uTurn: 2, turnLeft: 3}
+
+// An action is what a turmite does on finding a cell of some color while in some state: it paints the cell a new
+// color, turns, and changes to a new state, before moving forward one cell.
+type action struct {
+	write byte
+	turn  int
+	next  int
+}
+
+// Rule is a turmite's state-transition table, giving the action to take for each state and color.
+type Rule struct {
+	name   string
+	colors int
+	table  [][]action
+}
+
+// String returns the rulestring the rule was parsed from.
+func (r *Rule) String() string {
+	return r.name
+}
+
+// ParseRule parses a turmite rule in any of three forms:
+//
+//   - Ed Pegg's notation, as a list of states each holding a list of {write, turn, next state} actions for each color,
+//     such as {{{1,2,0},{0,8,0}}} for Langton's ant. Turns are 1 for none, 2 for right, 4 for a U-turn, and 8 for left.
+//   - The same notation written as digits in the names Golly gives turmite rules, such as Turmite_120080.
+//   - A string of turns for each color of a generalized Langton's ant, which paints each cell the next color along,
+//     such as RL for Langton's ant or LLRR. N and U stand for no turn and a U-turn.
+func ParseRule(rulestring string) (*Rule, error) {
+	r := &Rule{name: strings.TrimSpace(rulestring)}
+	malformed := fmt.Errorf("Malformed rule - must take the form '{{{#,#,#},...}}', 'Turmite_###...', or a string of L, R, N, and U: %q", rulestring)
+
+	var actions [][3]int
+	states := 1
+	spec := strings.Join(strings.Fields(r.name), "")
+	switch {
+	case strings.HasPrefix(spec, "{"):
+		var err error
+		actions, states, r.colors, err = parseBraces(spec)
+		if err != nil {
+			return nil, malformed
+		}
+	case strings.HasPrefix(strings.ToLower(spec), "turmite_"):
+		digits := spec[len("turmite_"):]
+		if len(digits) == 0 || len(digits)%3 != 0 {
+			return nil, malformed
+		}
+		for i := 0; i < len(digits); i += 3 {
+			var a [3]int
+			for j := range a {
+				if digits[i+j] < '0' || digits[i+j] > '9' {
+					return nil, malformed
+				}
+				a[j] = int(digits[i+j] - '0')
+			}
+			actions = append(actions, a)
+		}
+
+		// The colors aren't given, so assume there are as many as the turmite paints.
+		r.colors = 2
+		for _, a := range actions {
+			if a[0]+1 > r.colors {
+				r.colors = a[0] + 1
+			}
+		}
+		if len(actions)%r.colors != 0 {
+			return nil, malformed
+		}
+		states = len(actions) / r.colors
+	default:
+		letters := map[rune]int{'N': noTurn, 'R': turnRight, 'U': uTurn, 'L': turnLeft}
+		r.colors = len(spec)
+		if r.colors < 2 {
+			return nil, malformed
+		}
+		for i, c := range strings.ToUpper(spec) {
+			turn, ok := letters[c]
+			if !ok {
+				return nil, malformed
+			}
+			actions = append(actions, [3]int{(i + 1) % r.colors, turn, 0})
+		}
+	}
+
+	// Every combination of color, state, and heading needs its own state in RLE files.
+	if r.colors*(1+4*states) > 256 {
+		return nil, fmt.Errorf("Malformed rule - too many colors and states to save: %q", rulestring)
+	}
+
+	r.table = make([][]action, states)
+	for i, a := range actions {
+		if _, ok := turns[a[1]]; !ok || a[0] >= r.colors || a[2] >= states {
+			return nil, malformed
+		}
+		r.table[i/r.colors] = append(r.table[i/r.colors], action{write: byte(a[0]), turn: turns[a[1]], next: a[2]})
+	}
+	return r, nil
+}
+
+// parseBraces parses a turmite in Ed Pegg's notation, returning its actions in order along with the number of states
+// and colors.
+func parseBraces(spec string) ([][3]int, int, int, error) {
+	if len(spec) < 6 || !strings.HasPrefix(spec, "{{{") || !strings.HasSuffix(spec, "}}}") {
+		return nil, 0, 0, fmt.Errorf("missing braces")
+	}
+	var actions [][3]int
+	stateSpecs := strings.Split(spec[3:len(spec)-3], "}},{{")
+	colors := 0
+	for _, stateSpec := range stateSpecs {
+		actionSpecs := strings.Split(stateSpec, "},{")
+		if colors == 0 {
+			colors = len(actionSpecs)
+		}
+		if len(actionSpecs) != colors {
+			return nil, 0, 0, fmt.Errorf("every state must have an action for each color")
+		}
+		for _, actionSpec := range actionSpecs {
+			values := strings.Split(actionSpec, ",")
+			if len(values) != 3 {
+				return nil, 0, 0, fmt.Errorf("actions must have three values")
+			}
+			var a [3]int
+			for i, value := range values {
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 {
+					return nil, 0, 0, fmt.Errorf("bad value %q", value)
+				}
+				a[i] = n
+			}
+			actions = append(actions, a)
+		}
+	}
+	if colors < 2 {
+		return nil, 0, 0, fmt.Errorf("turmites need at least two colors")
+	}
+	return actions, len(stateSpecs), colors, nil
+}
@@ -0,0 +1,122 @@
+package turmite
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/rle"
+)
+
+func TestParseRule(t *testing.T) {
+	Convey("Each notation for Langton's ant should give the same table", t, func() {
+		for _, rule := range []string{"RL", "{{{1, 2, 0}, {0, 8, 0}}}", "Turmite_120080"} {
+			r, err := ParseRule(rule)
+			So(err, ShouldBeNil)
+			So(r.colors, ShouldEqual, 2)
+			So(r.table, ShouldResemble, [][]action{{{write: 1, turn: 1}, {write: 0, turn: 3}}})
+		}
+	})
+
+	Convey("Turmites may have more than one state", t, func() {
+		r, err := ParseRule("{{{1,2,1},{0,8,0}},{{1,1,0},{0,4,1}}}")
+		So(err, ShouldBeNil)
+		So(r.table, ShouldResemble, [][]action{
+			{{write: 1, turn: 1, next: 1}, {write: 0, turn: 3}},
+			{{write: 1, turn: 0}, {write: 0, turn: 2, next: 1}},
+		})
+	})
+
+	Convey("Malformed rules should return errors", t, func() {
+		for _, rule := range []string{"", "R", "RLX", "B3/S23", "{{{1,2,0}}}", "{{{1,3,0},{0,8,0}}}", "{{{1,2,1},{0,8,0}}}", "{{{1,2,0},{0,8}}}", "Turmite_12008", "Turmite_1200a0"} {
+			_, err := ParseRule(rule)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestNext(t *testing.T) {
+	Convey("Given Langton's ant on an empty field", t, func() {
+		m := New(200, 200)
+		m.ToggleCell(100, 100)
+
+		Convey("It should turn right on the first color, paint the cell, and move on", func() {
+			m.Next()
+			So(m.field[100*200+100], ShouldEqual, 1)
+			So(*m.ants[0], ShouldResemble, ant{x: 101, y: 100, heading: east})
+		})
+
+		Convey("After about ten thousand steps, it should build a highway, moving two cells diagonally every 104 steps", func() {
+			for i := 0; i < 11000; i++ {
+				m.Next()
+			}
+			x, y := m.ants[0].x, m.ants[0].y
+			for i := 0; i < 104; i++ {
+				m.Next()
+			}
+			dx, dy := m.ants[0].x-x, m.ants[0].y-y
+			So(dx*dx, ShouldEqual, 4)
+			So(dy*dy, ShouldEqual, 4)
+		})
+	})
+
+	Convey("Toggling a cell with an ant on it should remove the ant", t, func() {
+		m := New(10, 10)
+		m.ToggleCell(1, 1)
+		m.ToggleCell(5, 5)
+		m.ToggleCell(1, 1)
+		So(len(m.ants), ShouldEqual, 1)
+		So(m.ants[0].x, ShouldEqual, 5)
+	})
+}
+
+func TestModel(t *testing.T) {
+	Convey("Exporting the field should keep the ants", t, func() {
+		m := New(20, 20)
+		m.SetRule("{{{1,2,1},{0,8,0}},{{1,1,0},{0,4,1}}}")
+		m.ToggleCell(5, 5)
+		m.ToggleCell(12, 8)
+		for i := 0; i < 37; i++ {
+			m.Next()
+		}
+		f, err := rle.Unmarshal(m.Export().Marshal())
+		So(err, ShouldBeNil)
+
+		n := New(20, 20)
+		So(n.SetRule(f.Rule), ShouldBeNil)
+		n.Ingest(f)
+		So(n.rule.String(), ShouldEqual, m.rule.String())
+		So(n.field, ShouldResemble, m.field)
+		So(len(n.ants), ShouldEqual, 2)
+		for i := 0; i < 50; i++ {
+			m.Next()
+			n.Next()
+		}
+		So(n.Export().Marshal(), ShouldEqual, m.Export().Marshal())
+	})
+
+	Convey("Ingesting a pattern should replace the ants and colors already on the field", t, func() {
+		m := New(20, 20)
+		m.ToggleCell(3, 3)
+		for i := 0; i < 20; i++ {
+			m.Next()
+		}
+		f := m.Export()
+		m.Ingest(f)
+		m.Ingest(f)
+		So(len(m.ants), ShouldEqual, 1)
+		So(m.Export().Marshal(), ShouldEqual, f.Marshal())
+	})
+
+	Convey("A pattern with no ants should get one in the middle", t, func() {
+		f, err := rle.Unmarshal(`x = 2, y = 1, rule = LLRR
+AB!`)
+		So(err, ShouldBeNil)
+		m := New(10, 10)
+		So(m.SetRule(f.Rule), ShouldBeNil)
+		m.Ingest(f)
+		So(m.rule.colors, ShouldEqual, 4)
+		So(m.field[4*10+5], ShouldEqual, 2)
+		So(*m.ants[0], ShouldResemble, ant{x: 5, y: 5})
+	})
+}
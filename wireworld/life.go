@@ -0,0 +1,183 @@
+package wireworld
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand"
+	"strings"
+
+	"github.com/makyo/gogol/base"
+	"github.com/makyo/gogol/render"
+	"github.com/makyo/gogol/rle"
+)
+
+// Wireworld has four states, numbered the same way as in Golly so that patterns can be shared with it. Electrons
+// travel along conductors as a head followed by a tail:
+//
+//   - An empty cell stays empty.
+//   - An electron head becomes an electron tail.
+//   - An electron tail becomes a conductor.
+//   - A conductor becomes an electron head if one or two of its neighbors are electron heads.
+
+const (
+	empty     = 0
+	head      = 1
+	tail      = 2
+	conductor = 3
+)
+
+// Rule is the name Golly gives Wireworld, which is the only rule this model follows.
+const Rule = "WireWorld"
+
+type model struct {
+	width  int
+	height int
+	field  []byte
+}
+
+// heads counts the electron heads around the given cell, wrapping around the edges.
+func (m *model) heads(x, y int) int {
+	count := 0
+	for _, pos := range base.Neighbors(x, y, m.width, m.height) {
+		if m.field[pos] == head {
+			count++
+		}
+	}
+	return count
+}
+
+// nextState returns the state the given cell will have in the next generation.
+func (m *model) nextState(x, y int) byte {
+	switch state := m.field[y*m.width+x]; state {
+	case head:
+		return tail
+	case tail:
+		return conductor
+	case conductor:
+		if count := m.heads(x, y); count == 1 || count == 2 {
+			return head
+		}
+		return conductor
+	default:
+		return state
+	}
+}
+
+// Next evolves the field one generation.
+func (m *model) Next() {
+	next := make([]byte, len(m.field))
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			next[y*m.width+x] = m.nextState(x, y)
+		}
+	}
+	m.field = next
+}
+
+// Size returns the width and height of the field.
+func (m *model) Size() (int, int) {
+	return m.width, m.height
+}
+
+// State returns the state of the given cell.
+func (m *model) State(x, y int) int {
+	return int(m.field[y*m.width+x])
+}
+
+// SetState sets the state of the given cell.
+func (m *model) SetState(x, y, state int) {
+	m.field[y*m.width+x] = byte(state)
+}
+
+// NextState returns the state the given cell would have after the next generation, given its neighbors as they stand.
+func (m *model) NextState(x, y int) int {
+	return int(m.nextState(x, y))
+}
+
+// Populate generates a random tangle of wires, where each cell has a 1 in 3 chance of being a conductor, and each
+// conductor has a 1 in 10 chance of carrying an electron.
+func (m *model) Populate() {
+	for i, _ := range m.field {
+		m.field[i] = empty
+		if rand.Intn(3) == 0 {
+			m.field[i] = conductor
+			if rand.Intn(10) == 0 {
+				m.field[i] = head
+			}
+		}
+	}
+}
+
+// SetRule accepts only Wireworld's own rule, so that patterns made for it can be loaded but patterns for other rules
+// are rejected.
+func (m *model) SetRule(rulestring string) error {
+	if !strings.EqualFold(strings.TrimSpace(rulestring), Rule) {
+		return fmt.Errorf("This algorithm only supports %s, not %q", Rule, rulestring)
+	}
+	return nil
+}
+
+// Ingest sets the field to the given value. Cells in states which Wireworld doesn't have are left empty.
+func (m *model) Ingest(f *rle.RLEField) {
+	startX := (m.width - f.Width) / 2
+	startY := (m.height - f.Height) / 2
+	for y, row := range f.Field {
+		for x, _ := range row {
+			state := f.State(x, y)
+			if state == empty || state > conductor {
+				continue
+			}
+			m.field[((y+startY+m.height)%m.height)*m.width+(x+startX+m.width)%m.width] = byte(state)
+		}
+	}
+}
+
+// ToggleCell cycles the given cell from empty to a conductor, then to an electron head and tail, and back to empty.
+func (m *model) ToggleCell(x, y int) {
+	pos := y*m.width + x
+	switch m.field[pos] {
+	case empty:
+		m.field[pos] = conductor
+	case conductor:
+		m.field[pos] = head
+	case head:
+		m.field[pos] = tail
+	default:
+		m.field[pos] = empty
+	}
+}
+
+// Export returns the current state of the field.
+func (m *model) Export() *rle.RLEField {
+	f := rle.New(m.width, m.height)
+	f.SetRule(Rule)
+	for i, c := range m.field {
+		f.SetState(i%m.width, i/m.width, int(c))
+	}
+	return f
+}
+
+// Colors used for drawing cells, the same as Golly's: electron heads are blue, tails are white, and conductors are
+// orange.
+var stateColors = [4]color.Color{
+	head:      color.RGBA{0x00, 0x80, 0xff, 0xff},
+	tail:      color.RGBA{0xff, 0xff, 0xff, 0xff},
+	conductor: color.RGBA{0xff, 0x80, 0x00, 0xff},
+}
+
+// String builds the entire screen's worth of cells to be printed by returning a • for each cell other than an empty
+// one, colored by its state, or a space for an empty cell.
+func (m *model) String() string {
+	return render.ColorRuns(m.field, m.width, func(state byte) color.Color {
+		return stateColors[state]
+	})
+}
+
+// New creates an empty model of the given size.
+func New(width, height int) *model {
+	return &model{
+		width:  width,
+		height: height,
+		field:  make([]byte, width*height),
+	}
+}
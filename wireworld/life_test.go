@@ -0,0 +1,104 @@
+package wireworld
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/makyo/gogol/rle"
+)
+
+// Circuits used for testing, in Golly's format. Electrons travel from left to right.
+const (
+	// An electron heading into a diode in the direction it lets through.
+	diodeForward = `x = 10, y = 3, rule = WireWorld
+3.2C$BA2C.5C$3.2C!`
+
+	// An electron heading into the same diode the other way.
+	diodeBackward = `x = 10, y = 3, rule = WireWorld
+3.2C$4C.3CAB$3.2C!`
+
+	// A loop of six cells with a single electron in it, which sends an electron down the wire every six generations.
+	clock = `x = 10, y = 3, rule = WireWorld
+.2C$C2.7C$.BA!`
+
+	// An exclusive or gate with inputs on the top and bottom rows, and its output in the middle.
+	xor = `x = 13, y = 7, rule = WireWorld
+4C$4.C$3.4C$3.C2.7C$3.4C$4.C$4C!`
+)
+
+// load ingests a circuit into a model with a margin of empty cells all around, so that electrons can't wrap around the
+// edges.
+func load(circuit string) *model {
+	f, err := rle.Unmarshal(circuit)
+	So(err, ShouldBeNil)
+	m := New(f.Width+4, f.Height+4)
+	m.Ingest(f)
+	return m
+}
+
+// heads runs a model for some number of generations, and returns the generations after which the cell at the given
+// position of the circuit was an electron head.
+func heads(m *model, x, y, generations int) []int {
+	var found []int
+	for i := 1; i <= generations; i++ {
+		m.Next()
+		if m.field[(y+2)*m.width+x+2] == head {
+			found = append(found, i)
+		}
+	}
+	return found
+}
+
+func TestCircuits(t *testing.T) {
+	Convey("A diode should let electrons through in only one direction", t, func() {
+		So(heads(load(diodeForward), 9, 1, 20), ShouldResemble, []int{8})
+		So(heads(load(diodeBackward), 0, 1, 20), ShouldBeEmpty)
+	})
+
+	Convey("A clock should send out an electron every six generations", t, func() {
+		So(heads(load(clock), 9, 1, 30), ShouldResemble, []int{7, 13, 19, 25})
+	})
+
+	Convey("An exclusive or gate should only send out an electron when exactly one input has one", t, func() {
+		for _, inputs := range [][]int{{0}, {6}, {0, 6}, {}} {
+			m := load(xor)
+			for _, y := range inputs {
+				m.field[(y+2)*m.width+2] = tail
+				m.field[(y+2)*m.width+3] = head
+			}
+			var expected []int
+			if len(inputs) == 1 {
+				expected = []int{11}
+			}
+			So(heads(m, 12, 3, 30), ShouldResemble, expected)
+		}
+	})
+}
+
+func TestModel(t *testing.T) {
+	Convey("Exporting a circuit should keep every state and the rule", t, func() {
+		m := load(clock)
+		f, err := rle.Unmarshal(m.Export().Marshal())
+		So(err, ShouldBeNil)
+		So(f.Rule, ShouldEqual, Rule)
+		So(m.SetRule(f.Rule), ShouldBeNil)
+		for i, c := range m.field {
+			So(f.State(i%m.width, i/m.width), ShouldEqual, int(c))
+		}
+	})
+
+	Convey("Other rules should be rejected", t, func() {
+		So(New(1, 1).SetRule("B3/S23"), ShouldNotBeNil)
+	})
+
+	Convey("Toggling a cell should cycle through every state", t, func() {
+		m := New(1, 1)
+		var states []byte
+		for i := 0; i < 4; i++ {
+			m.ToggleCell(0, 0)
+			states = append(states, m.field[0])
+		}
+		So(states, ShouldResemble, []byte{conductor, head, tail, empty})
+	})
+}